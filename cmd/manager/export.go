@@ -0,0 +1,185 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+)
+
+// exportedResources are the bindings and namespaces an RBAC Definition is exported from
+type exportedResources struct {
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+	roleBindings        []rbacv1.RoleBinding
+	namespaces          []corev1.Namespace
+}
+
+// runExport prints an RBAC Definition that reproduces the existing bindings of a cluster
+func runExport(args []string) error {
+	var files stringSliceFlag
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Var(&files, "f", "File containing Cluster Role Bindings, Role Bindings, and Namespaces to export instead of the cluster, may be repeated. Use - to read from stdin.")
+	name := fs.String("name", "exported", "Name of the generated RBAC Definition")
+	namespaceSelectors := fs.Bool("namespace-selectors", true, "Replace Role Bindings in every namespace with a set of shared labels by a namespaceSelector")
+	output := fs.String("o", "yaml", "Output format (yaml, json)")
+	config.RegisterFlags(fs)
+	_ = fs.Parse(args)
+
+	var resources exportedResources
+	var err error
+	if len(files) > 0 {
+		resources, err = readExportedResources(files)
+	} else {
+		resources, err = listExportedResources()
+	}
+	if err != nil {
+		return err
+	}
+
+	rbacDef := reconciler.Export(*name, resources.clusterRoleBindings, resources.roleBindings, resources.namespaces, *namespaceSelectors)
+
+	return writeExport(os.Stdout, &rbacDef, *output)
+}
+
+// listExportedResources lists the bindings and namespaces of the current cluster
+func listExportedResources() (exportedResources, error) {
+	resources := exportedResources{}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return resources, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return resources, err
+	}
+
+	crbs, err := clientset.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return resources, err
+	}
+	resources.clusterRoleBindings = crbs.Items
+
+	rbs, err := clientset.RbacV1().RoleBindings("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return resources, err
+	}
+	resources.roleBindings = rbs.Items
+
+	namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return resources, err
+	}
+	resources.namespaces = namespaces.Items
+
+	return resources, nil
+}
+
+// readExportedResources returns the bindings and namespaces found in a set of
+// files, including those in a List such as the output of
+// kubectl get clusterrolebindings,rolebindings,namespaces -A -o yaml
+func readExportedResources(paths []string) (exportedResources, error) {
+	resources := exportedResources{}
+
+	for _, path := range paths {
+		clusterRoleBindings, err := readObjects[rbacv1.ClusterRoleBinding](path, "ClusterRoleBinding")
+		if err != nil {
+			return resources, err
+		}
+		resources.clusterRoleBindings = append(resources.clusterRoleBindings, clusterRoleBindings...)
+
+		roleBindings, err := readObjects[rbacv1.RoleBinding](path, "RoleBinding")
+		if err != nil {
+			return resources, err
+		}
+		resources.roleBindings = append(resources.roleBindings, roleBindings...)
+
+		namespaces, err := readObjects[corev1.Namespace](path, "Namespace")
+		if err != nil {
+			return resources, err
+		}
+		resources.namespaces = append(resources.namespaces, namespaces...)
+	}
+
+	return resources, nil
+}
+
+// writeExport writes an RBAC Definition without its empty fields and status
+func writeExport(w io.Writer, object any, output string) error {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	exported := map[string]any{}
+	err = json.Unmarshal(raw, &exported)
+	if err != nil {
+		return err
+	}
+	delete(exported, "status")
+	pruneEmpty(exported)
+
+	switch output {
+	case "yaml":
+		out, err := yaml.Marshal(exported)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(exported)
+	default:
+		return fmt.Errorf("unknown output format %v", output)
+	}
+}
+
+// pruneEmpty removes null values and empty objects from decoded JSON
+func pruneEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		for key, item := range v {
+			if pruneEmpty(item) {
+				delete(v, key)
+			}
+		}
+		return len(v) == 0
+	case []any:
+		for _, item := range v {
+			pruneEmpty(item)
+		}
+	}
+	return false
+}
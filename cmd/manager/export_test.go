@@ -0,0 +1,54 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadExportedResources(t *testing.T) {
+	path := writeFile(t, `apiVersion: v1
+kind: List
+items:
+- apiVersion: rbac.authorization.k8s.io/v1
+  kind: ClusterRoleBinding
+  metadata:
+    name: devs-view
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: web
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBindingList
+items:
+- metadata:
+    name: devs-edit
+    namespace: web
+`)
+
+	resources, err := readExportedResources([]string{path})
+	assert.NoError(t, err)
+	assert.Len(t, resources.clusterRoleBindings, 1)
+	assert.Equal(t, "devs-view", resources.clusterRoleBindings[0].Name)
+	assert.Len(t, resources.roleBindings, 1)
+	assert.Equal(t, "web", resources.roleBindings[0].Namespace)
+	assert.Len(t, resources.namespaces, 1)
+	assert.Equal(t, "web", resources.namespaces[0].Name)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// stringSliceFlag is a flag that can be repeated
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// stdin is read once, so several kinds of objects can be read from it
+var stdin = sync.OnceValues(func() ([]byte, error) {
+	return io.ReadAll(os.Stdin)
+})
+
+// decodeDocuments calls handle for every YAML or JSON document in a file, "-" reads from stdin
+func decodeDocuments(path string, handle func(typeMeta metav1.TypeMeta, raw []byte) error) error {
+	var reader io.Reader
+	if path == "-" {
+		content, err := stdin()
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(content)
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error decoding %v: %w", path, err)
+		}
+
+		typeMeta := metav1.TypeMeta{}
+		err = json.Unmarshal(raw, &typeMeta)
+		if err != nil {
+			return fmt.Errorf("error decoding %v: %w", path, err)
+		}
+
+		err = handle(typeMeta, raw)
+		if err != nil {
+			return fmt.Errorf("error decoding %v: %w", path, err)
+		}
+	}
+}
+
+// readObjects returns the objects of a kind found in a file, including those
+// in a list of that kind or a List
+func readObjects[T any](path, kind string) ([]T, error) {
+	objects := []T{}
+
+	err := decodeDocuments(path, func(typeMeta metav1.TypeMeta, raw []byte) error {
+		items := []json.RawMessage{raw}
+		switch typeMeta.Kind {
+		case kind:
+		case kind + "List", "List":
+			list := struct {
+				Items []json.RawMessage `json:"items"`
+			}{}
+			err := json.Unmarshal(raw, &list)
+			if err != nil {
+				return err
+			}
+			items = list.Items
+		default:
+			return nil
+		}
+
+		for _, item := range items {
+			itemType := metav1.TypeMeta{}
+			err := json.Unmarshal(item, &itemType)
+			if err != nil {
+				return err
+			}
+			// Items of typed lists usually leave out their kind
+			if itemType.Kind != "" && itemType.Kind != kind {
+				continue
+			}
+
+			var object T
+			err = json.Unmarshal(item, &object)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, object)
+		}
+		return nil
+	})
+
+	return objects, err
+}
+
+// readRbacDefinitions returns the RBAC Definitions found in a set of files, other kinds are ignored
+func readRbacDefinitions(paths []string) ([]rbacmanagerv1beta1.RBACDefinition, error) {
+	rbacDefs := []rbacmanagerv1beta1.RBACDefinition{}
+
+	for _, path := range paths {
+		found, err := readObjects[rbacmanagerv1beta1.RBACDefinition](path, "RBACDefinition")
+		if err != nil {
+			return nil, err
+		}
+		rbacDefs = append(rbacDefs, found...)
+	}
+
+	return rbacDefs, nil
+}
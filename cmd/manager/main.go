@@ -22,25 +22,55 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/fairwindsops/rbac-manager/pkg/apis"
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
 	"github.com/fairwindsops/rbac-manager/pkg/controller"
+	"github.com/fairwindsops/rbac-manager/pkg/kube"
 	"github.com/fairwindsops/rbac-manager/pkg/metrics"
-	"github.com/fairwindsops/rbac-manager/pkg/watcher"
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+	rbacwebhook "github.com/fairwindsops/rbac-manager/pkg/webhook"
 	"github.com/fairwindsops/rbac-manager/version"
 )
 
 var logLevel = flag.String("log-level", "info", "Log level (debug, info, warn, error)")
 var addr = flag.String("metrics-address", ":8042", "The address to serve prometheus metrics.")
+var enableWebhook = flag.Bool("enable-webhook", false, "Serve the admission webhooks: the validating webhooks for RBAC Definitions and Namespaced RBAC Definitions, and the mutating webhook that records who requested and approved changes.")
+var webhookPort = flag.Int("webhook-port", 9443, "The port to serve the admission webhook on.")
+var approvalRequiredClusterRoles = flag.String("approval-required-cluster-roles", "", "Comma separated list of ClusterRoles that are only bound once a second user approves the RBAC Definition.")
+var namespacedClusterRoles = flag.String("namespaced-cluster-roles", "", "Comma separated list of ClusterRoles that Namespaced RBAC Definitions may bind. Namespaced RBAC Definitions can only bind Roles in their own namespace otherwise.")
+var adoptExisting = flag.Bool("adopt-existing", false, "Let every RBAC Definition take ownership of existing bindings and Service Accounts with the same name that are not owned by anything else.")
+var leaderElect = flag.Bool("leader-elect", false, "Use leader election so that only one of several replicas reconciles RBAC Definitions at a time.")
+var leaderElectionNamespace = flag.String("leader-election-namespace", "", "The namespace of the leader election Lease. Defaults to the namespace RBAC Manager runs in.")
+var leaderElectionID = flag.String("leader-election-id", "rbac-manager", "The name of the leader election Lease.")
+var leaseDuration = flag.Duration("leader-election-lease-duration", 15*time.Second, "How long replicas wait before taking over from a leader that stopped renewing its Lease.")
+var renewDeadline = flag.Duration("leader-election-renew-deadline", 10*time.Second, "How long the leader keeps trying to renew its Lease before giving up leadership.")
+var retryPeriod = flag.Duration("leader-election-retry-period", 2*time.Second, "How long replicas wait between attempts to acquire or renew the Lease.")
+var webhookCertDir = flag.String("webhook-cert-dir", "", "The directory containing tls.crt and tls.key for the admission webhook. Defaults to <temp-dir>/k8s-webhook-server/serving-certs.")
+
+// commands are run instead of the manager when given as the first argument
+var commands = map[string]func(args []string) error{
+	"export": runExport,
+	"plan":   runPlan,
+	"render": runRender,
+}
 
 func init() {
 	klog.InitFlags(nil)
@@ -82,7 +112,29 @@ func (a *slogToLogrAdapter) WithName(name string) logr.LogSink {
 	return &slogToLogrAdapter{logger: a.logger.With("name", name)}
 }
 
+// parseClusterRoles returns the ClusterRoles in a comma separated list
+func parseClusterRoles(list string) sets.Set[string] {
+	clusterRoles := sets.New[string]()
+	for _, clusterRole := range strings.Split(list, ",") {
+		if clusterRole = strings.TrimSpace(clusterRole); clusterRole != "" {
+			clusterRoles.Insert(clusterRole)
+		}
+	}
+	return clusterRoles
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+			if err := command(os.Args[2:]); err != nil {
+				slog.Error("unable to run "+os.Args[1], "error", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	flag.Parse()
 
 	level := parseLogLevel(*logLevel)
@@ -100,6 +152,18 @@ func main() {
 	slog.Info("rbac-manager running", "version", version.Version)
 	slog.Info("----------------------------------")
 
+	options := reconciler.Options{
+		ApprovalRequiredClusterRoles: parseClusterRoles(*approvalRequiredClusterRoles),
+		NamespacedClusterRoles:       parseClusterRoles(*namespacedClusterRoles),
+		AdoptExisting:                *adoptExisting,
+	}
+	// Without the webhook anyone who can edit an RBAC Definition could set
+	//   the approval annotations by hand
+	if options.ApprovalRequiredClusterRoles.Len() > 0 && !*enableWebhook {
+		slog.Error("approvals can not be verified unless the webhook is enabled", "clusterRoles", *approvalRequiredClusterRoles)
+		os.Exit(1)
+	}
+
 	// Get a config to talk to the apiserver
 	slog.Debug("Setting up client for manager")
 	cfg, err := config.GetConfig()
@@ -108,9 +172,37 @@ func main() {
 		os.Exit(1)
 	}
 
+	managedSelector := labels.SelectorFromSet(kube.Labels)
+
 	// Create a new Cmd to provide shared dependencies and start components
 	slog.Debug("Setting up manager")
-	mgr, err := manager.New(cfg, manager.Options{})
+	mgr, err := manager.New(cfg, manager.Options{
+		// Only resources labeled as managed by RBAC Manager are cached, Roles
+		//   and Namespaces are cached in full to resolve namespaceSelectors
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.ServiceAccount{}:     {Label: managedSelector},
+				&corev1.Secret{}:             {Label: managedSelector},
+				&rbacv1.ClusterRole{}:        {Label: managedSelector},
+				&rbacv1.ClusterRoleBinding{}: {Label: managedSelector},
+				&rbacv1.RoleBinding{}:        {Label: managedSelector},
+			},
+			DefaultWatchErrorHandler: controller.WatchErrorHandler,
+		},
+		// Controllers only run on the leader, every replica serves the webhook
+		//   and metrics
+		LeaderElection:                *leaderElect,
+		LeaderElectionNamespace:       *leaderElectionNamespace,
+		LeaderElectionID:              *leaderElectionID,
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 leaseDuration,
+		RenewDeadline:                 renewDeadline,
+		RetryPeriod:                   retryPeriod,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    *webhookPort,
+			CertDir: *webhookCertDir,
+		}),
+	})
 	if err != nil {
 		slog.Error("unable to set up overall controller manager", "error", err)
 		os.Exit(1)
@@ -118,6 +210,13 @@ func main() {
 
 	slog.Info("Registering components")
 
+	options.LoadPolicies = func() (rbacmanagerv1beta1.RBACManagerPolicyList, error) {
+		return kube.ListRbacManagerPolicies(mgr.GetClient())
+	}
+	options.LoadSubjectSets = func() (rbacmanagerv1beta1.SubjectSetList, error) {
+		return kube.ListSubjectSets(mgr.GetClient())
+	}
+
 	// Setup Scheme for all resources
 	slog.Debug("Setting up scheme")
 	if err := apis.AddToScheme(mgr.GetScheme()); err != nil {
@@ -127,14 +226,18 @@ func main() {
 
 	// Setup all Controllers
 	slog.Debug("Setting up controller")
-	if err := controller.Add(mgr); err != nil {
+	if err := controller.Add(mgr, options); err != nil {
 		slog.Error("unable to register controller to the manager", "error", err)
 		os.Exit(1)
 	}
 
-	// Watch Related Resources
-	slog.Info("Watching resources related to RBAC Definitions")
-	watcher.WatchRelatedResources()
+	if *enableWebhook {
+		slog.Debug("Setting up webhook")
+		if err := rbacwebhook.Add(mgr, options); err != nil {
+			slog.Error("unable to register webhook to the manager", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// Start metrics endpoint
 	go func() {
@@ -0,0 +1,180 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/kube"
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+)
+
+// definitionPlan is the plan for a single RBAC Definition
+type definitionPlan struct {
+	RBACDefinition           string `json:"rbacDefinition"`
+	*rbacmanagerv1beta1.Plan `json:",inline"`
+}
+
+// runPlan prints the changes RBAC Manager would make for a set of RBAC Definitions
+func runPlan(args []string) error {
+	var files stringSliceFlag
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	fs.Var(&files, "f", "File containing RBAC Definitions to plan, may be repeated. Use - to read from stdin.")
+	name := fs.String("name", "", "Name of an RBAC Definition in the cluster to plan instead of a file")
+	output := fs.String("o", "text", "Output format (text, json)")
+	approvalRequired := fs.String("approval-required-cluster-roles", "", "Comma separated list of ClusterRoles that are only bound once a second user approves the RBAC Definition.")
+	namespaced := fs.String("namespaced-cluster-roles", "", "Comma separated list of ClusterRoles that Namespaced RBAC Definitions may bind.")
+	adopt := fs.Bool("adopt-existing", false, "Let every RBAC Definition take ownership of existing bindings and Service Accounts with the same name that are not owned by anything else.")
+	config.RegisterFlags(fs)
+	_ = fs.Parse(args)
+
+	if len(files) == 0 && *name == "" {
+		return errors.New("either -f or -name is required")
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	var rbacDefs []rbacmanagerv1beta1.RBACDefinition
+	if *name != "" {
+		rbacDef, err := kube.GetRbacDefinition(*name)
+		if err != nil {
+			return err
+		}
+		rbacDefs = append(rbacDefs, rbacDef)
+	}
+
+	fileRbacDefs, err := readRbacDefinitions(files)
+	if err != nil {
+		return err
+	}
+
+	for _, rbacDef := range fileRbacDefs {
+		// The UID of an existing RBAC Definition is part of the owner references of the resources it manages
+		existing, err := kube.GetRbacDefinition(rbacDef.Name)
+		if err == nil {
+			rbacDef.UID = existing.UID
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+		rbacDefs = append(rbacDefs, rbacDef)
+	}
+
+	options := reconciler.Options{
+		LoadPolicies:                 kube.GetRbacManagerPolicies,
+		LoadSubjectSets:              kube.GetSubjectSets,
+		ApprovalRequiredClusterRoles: parseClusterRoles(*approvalRequired),
+		NamespacedClusterRoles:       parseClusterRoles(*namespaced),
+		AdoptExisting:                *adopt,
+	}
+
+	// The plan for the valid parts of every RBAC Definition is printed before
+	// the errors are reported
+	plans := []definitionPlan{}
+	planErrs := []error{}
+	for _, rbacDef := range rbacDefs {
+		r := reconciler.Reconciler{Options: options, Clientset: clientset}
+		plan, err := r.Plan(&rbacDef)
+		if err != nil {
+			planErrs = append(planErrs, fmt.Errorf("error planning RBACDefinition %v: %w", rbacDef.Name, err))
+		}
+		if plan != nil {
+			plans = append(plans, definitionPlan{RBACDefinition: rbacDef.Name, Plan: plan})
+		}
+	}
+
+	switch *output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(plans)
+		if err != nil {
+			return err
+		}
+	case "text":
+		for _, plan := range plans {
+			writePlan(os.Stdout, plan)
+		}
+	default:
+		return fmt.Errorf("unknown output format %v", *output)
+	}
+
+	return errors.Join(planErrs...)
+}
+
+// writePlan writes a human readable description of a plan
+func writePlan(w io.Writer, plan definitionPlan) {
+	fmt.Fprintf(w, "RBACDefinition %v\n", plan.RBACDefinition)
+	if reconciler.PlanIsEmpty(plan.Plan) {
+		fmt.Fprintln(w, "  No changes")
+		return
+	}
+
+	for _, change := range plan.Create {
+		fmt.Fprintf(w, "  + %v\n", describeChange(change))
+	}
+	for _, change := range plan.Update {
+		fmt.Fprintf(w, "  ~ %v\n", describeChange(change))
+	}
+	for _, change := range plan.Delete {
+		fmt.Fprintf(w, "  - %v\n", describeChange(change))
+	}
+}
+
+func describeChange(change rbacmanagerv1beta1.PlannedChange) string {
+	description := fmt.Sprintf("%v %v", change.Kind, change.Name)
+	if change.Namespace != "" {
+		description = fmt.Sprintf("%v %v/%v", change.Kind, change.Namespace, change.Name)
+	}
+
+	if change.RoleRef != nil {
+		description = fmt.Sprintf("%v: %v/%v -> %v", description, change.RoleRef.Kind, change.RoleRef.Name, describeSubjects(change.Subjects))
+	}
+
+	return description
+}
+
+func describeSubjects(subjects []rbacv1.Subject) string {
+	descriptions := []string{}
+	for _, subject := range subjects {
+		if subject.Namespace != "" {
+			descriptions = append(descriptions, fmt.Sprintf("%v/%v/%v", subject.Kind, subject.Namespace, subject.Name))
+		} else {
+			descriptions = append(descriptions, fmt.Sprintf("%v/%v", subject.Kind, subject.Name))
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+)
+
+// runRender prints the resources a set of RBAC Definitions would create without talking to a cluster
+func runRender(args []string) error {
+	var files stringSliceFlag
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	fs.Var(&files, "f", "File containing RBAC Definitions to render, may be repeated. Use - to read from stdin.")
+	namespacesFile := fs.String("namespaces", "", "File containing the Namespaces used to resolve namespaceSelectors, e.g. the output of kubectl get namespaces -o yaml")
+	rolesFile := fs.String("roles", "", "File containing the Roles used to resolve namespaceSelectors of Role bindings, e.g. the output of kubectl get roles -A -o yaml")
+	configMapsFile := fs.String("configmaps", "", "File containing the ConfigMaps subjects are read from, e.g. the output of kubectl get configmap -n identity teams -o yaml")
+	policiesFile := fs.String("policies", "", "File containing RBACManagerPolicies to enforce, e.g. the output of kubectl get rbacmanagerpolicies -o yaml")
+	subjectSetsFile := fs.String("subject-sets", "", "File containing the SubjectSets RBAC Bindings refer to, e.g. the output of kubectl get subjectsets -o yaml")
+	output := fs.String("o", "yaml", "Output format (yaml, json)")
+	_ = fs.Parse(args)
+
+	if len(files) == 0 {
+		return errors.New("-f is required")
+	}
+
+	options := reconciler.Options{}
+
+	if *policiesFile != "" {
+		policies, err := readObjects[rbacmanagerv1beta1.RBACManagerPolicy](*policiesFile, "RBACManagerPolicy")
+		if err != nil {
+			return err
+		}
+		options.LoadPolicies = func() (rbacmanagerv1beta1.RBACManagerPolicyList, error) {
+			return rbacmanagerv1beta1.RBACManagerPolicyList{Items: policies}, nil
+		}
+	}
+
+	if *subjectSetsFile != "" {
+		subjectSets, err := readObjects[rbacmanagerv1beta1.SubjectSet](*subjectSetsFile, "SubjectSet")
+		if err != nil {
+			return err
+		}
+		options.LoadSubjectSets = func() (rbacmanagerv1beta1.SubjectSetList, error) {
+			return rbacmanagerv1beta1.SubjectSetList{Items: subjectSets}, nil
+		}
+	}
+
+	rbacDefs, err := readRbacDefinitions(files)
+	if err != nil {
+		return err
+	}
+
+	existing := []runtime.Object{}
+	if *namespacesFile != "" {
+		namespaces, err := readObjects[*corev1.Namespace](*namespacesFile, "Namespace")
+		if err != nil {
+			return err
+		}
+		for _, namespace := range namespaces {
+			existing = append(existing, namespace)
+		}
+	}
+
+	if *rolesFile != "" {
+		roles, err := readObjects[*rbacv1.Role](*rolesFile, "Role")
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			existing = append(existing, role)
+		}
+	}
+
+	if *configMapsFile != "" {
+		configMaps, err := readObjects[*corev1.ConfigMap](*configMapsFile, "ConfigMap")
+		if err != nil {
+			return err
+		}
+		for _, configMap := range configMaps {
+			existing = append(existing, configMap)
+		}
+	}
+
+	objects, err := render(rbacDefs, options, existing)
+	if err != nil {
+		return err
+	}
+
+	return writeObjects(os.Stdout, objects, *output)
+}
+
+// render returns the resources a set of RBAC Definitions would create, given
+// the existing resources of a cluster
+func render(rbacDefs []rbacmanagerv1beta1.RBACDefinition, options reconciler.Options, existing []runtime.Object) ([]runtime.Object, error) {
+	clientset := fake.NewClientset(existing...)
+	objects := []runtime.Object{}
+
+	for _, rbacDef := range rbacDefs {
+		p := reconciler.Parser{Options: options, Clientset: clientset}
+		err := p.Parse(rbacDef)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing RBACDefinition %v: %w", rbacDef.Name, err)
+		}
+
+		for _, cr := range p.ClusterRoles() {
+			cr.TypeMeta = metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"}
+			objects = append(objects, &cr)
+		}
+		for _, role := range p.Roles() {
+			role.TypeMeta = metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"}
+			objects = append(objects, &role)
+		}
+		for _, sa := range p.ServiceAccounts() {
+			sa.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"}
+			objects = append(objects, &sa)
+		}
+		for _, secret := range p.Secrets() {
+			secret.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}
+			objects = append(objects, &secret)
+		}
+		// The API server defaults the API group of role references, setting it here
+		//   allows the output to be diffed against a cluster
+		for _, crb := range p.ClusterRoleBindings() {
+			crb.TypeMeta = metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"}
+			crb.RoleRef.APIGroup = rbacv1.GroupName
+			objects = append(objects, &crb)
+		}
+		for _, rb := range p.RoleBindings() {
+			rb.TypeMeta = metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"}
+			rb.RoleRef.APIGroup = rbacv1.GroupName
+			objects = append(objects, &rb)
+		}
+	}
+
+	return objects, nil
+}
+
+// writeObjects writes objects as multi-document YAML or as a JSON List
+func writeObjects(w io.Writer, objects []runtime.Object, output string) error {
+	switch output {
+	case "yaml":
+		for _, object := range objects {
+			out, err := yaml.Marshal(object)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "---\n%s", out)
+		}
+		return nil
+	case "json":
+		items := []runtime.RawExtension{}
+		for _, object := range objects {
+			items = append(items, runtime.RawExtension{Object: object})
+		}
+		list := corev1.List{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+			Items:    items,
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	default:
+		return fmt.Errorf("unknown output format %v", output)
+	}
+}
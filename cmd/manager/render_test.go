@@ -0,0 +1,200 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+)
+
+func TestReadObjects(t *testing.T) {
+	path := writeFile(t, `apiVersion: v1
+kind: Namespace
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: NamespaceList
+items:
+- metadata:
+    name: api
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: db
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: teams
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+`)
+
+	namespaces, err := readObjects[*corev1.Namespace](path, "Namespace")
+	assert.NoError(t, err)
+	names := []string{}
+	for _, namespace := range namespaces {
+		names = append(names, namespace.Name)
+	}
+	assert.Equal(t, []string{"web", "api", "db"}, names)
+
+	configMaps, err := readObjects[corev1.ConfigMap](path, "ConfigMap")
+	assert.NoError(t, err)
+	assert.Len(t, configMaps, 2)
+
+	_, err = readObjects[corev1.ConfigMap](filepath.Join(t.TempDir(), "missing.yaml"), "ConfigMap")
+	assert.Error(t, err)
+}
+
+func TestReadRbacDefinitions(t *testing.T) {
+	path := writeFile(t, `apiVersion: rbacmanager.reactiveops.io/v1beta1
+kind: RBACDefinition
+metadata:
+  name: web
+---
+apiVersion: rbacmanager.reactiveops.io/v1beta1
+kind: RBACDefinitionList
+items:
+- metadata:
+    name: api
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: rbacmanager.reactiveops.io/v1beta1
+  kind: RBACDefinition
+  metadata:
+    name: db
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: db
+`)
+
+	rbacDefs, err := readRbacDefinitions([]string{path})
+	assert.NoError(t, err)
+	names := []string{}
+	for _, rbacDef := range rbacDefs {
+		names = append(names, rbacDef.Name)
+	}
+	assert.Equal(t, []string{"web", "api", "db"}, names)
+}
+
+func TestRender(t *testing.T) {
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "render-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci-bot", Namespace: "bots"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "cluster-admin",
+		}, {
+			ClusterRole: "view",
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole:       "edit",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "devs"}},
+		}},
+	}}
+
+	existing := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "devs"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "db"}},
+	}
+	options := reconciler.Options{
+		LoadPolicies: func() (rbacmanagerv1beta1.RBACManagerPolicyList, error) {
+			return rbacmanagerv1beta1.RBACManagerPolicyList{Items: []rbacmanagerv1beta1.RBACManagerPolicy{{
+				ObjectMeta:         metav1.ObjectMeta{Name: "baseline"},
+				DeniedClusterRoles: []string{"cluster-admin"},
+			}}}, nil
+		},
+	}
+
+	objects, err := render([]rbacmanagerv1beta1.RBACDefinition{rbacDef}, options, existing)
+	assert.NoError(t, err)
+
+	rendered := []string{}
+	for _, object := range objects {
+		objectMeta, ok := object.(metav1.ObjectMetaAccessor)
+		assert.True(t, ok)
+		rendered = append(rendered, object.GetObjectKind().GroupVersionKind().Kind+"/"+objectMeta.GetObjectMeta().GetName())
+	}
+	assert.Equal(t, []string{
+		"ServiceAccount/ci-bot",
+		"ClusterRoleBinding/render-example-devs-view",
+		"RoleBinding/render-example-devs-edit",
+	}, rendered)
+
+	rb := objects[2].(*rbacv1.RoleBinding)
+	assert.Equal(t, "web", rb.Namespace)
+	assert.Equal(t, rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "edit"}, rb.RoleRef)
+
+	rbacDef.RBACBindings[0].ClusterRoleBindings[0].ClusterRole = ""
+	_, err = render([]rbacmanagerv1beta1.RBACDefinition{rbacDef}, options, existing)
+	assert.Error(t, err)
+}
+
+func TestWriteObjects(t *testing.T) {
+	objects := []runtime.Object{&corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-bot", Namespace: "bots"},
+	}}
+
+	out := bytes.Buffer{}
+	err := writeObjects(&out, objects, "yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "---\napiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: ci-bot\n  namespace: bots\n", out.String())
+
+	out.Reset()
+	err = writeObjects(&out, objects, "json")
+	assert.NoError(t, err)
+	list := corev1.List{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &list))
+	assert.Equal(t, "List", list.Kind)
+	assert.Len(t, list.Items, 1)
+
+	err = writeObjects(&out, objects, "xml")
+	assert.Error(t, err)
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "objects.yaml")
+	err := os.WriteFile(path, []byte(content), 0o600)
+	assert.NoError(t, err)
+	return path
+}
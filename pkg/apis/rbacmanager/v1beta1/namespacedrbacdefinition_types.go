@@ -0,0 +1,47 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NamespacedRBACDefinition is the Schema for the namespacedrbacdefinitions API.
+// It is limited to Role Bindings and Service Accounts in its own namespace.
+// +k8s:openapi-gen=true
+type NamespacedRBACDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	RBACBindings      []RBACBinding        `json:"rbacBindings"`
+	Status            RBACDefinitionStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NamespacedRBACDefinitionList contains a list of NamespacedRBACDefinition
+type NamespacedRBACDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespacedRBACDefinition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespacedRBACDefinition{}, &NamespacedRBACDefinitionList{})
+}
@@ -21,32 +21,95 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// Subject is an expansion on the rbacv1.Subject to allow definition of ImagePullSecrets for a Service Account
+// Subject is an expansion on the rbacv1.Subject to allow definition of ImagePullSecrets, Labels, Annotations,
+// and long-lived token Secrets for a Service Account.
+// A Subject with FromConfigMap stands for one subject of its Kind for every name listed in the ConfigMap key.
 type Subject struct {
 	rbacv1.Subject               `json:",inline"`
-	ImagePullSecrets             []string `json:"imagePullSecrets"`
-	AutomountServiceAccountToken *bool    `json:"automountServiceAccountToken,omitempty"`
+	ImagePullSecrets             []string               `json:"imagePullSecrets"`
+	AutomountServiceAccountToken *bool                  `json:"automountServiceAccountToken,omitempty"`
+	Labels                       map[string]string      `json:"labels,omitempty"`
+	Annotations                  map[string]string      `json:"annotations,omitempty"`
+	TokenSecrets                 []string               `json:"tokenSecrets,omitempty"`
+	FromConfigMap                *ConfigMapKeyReference `json:"fromConfigMap,omitempty"`
 }
 
-// RBACBinding is a specification for a RBACBinding resource
+// ConfigMapKeyReference refers to a key of a ConfigMap. The value of the key
+// lists one name per line, or separated by commas.
+type ConfigMapKeyReference struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
+
+// RBACBinding is a specification for a RBACBinding resource. Resources are only
+// created for it from NotBefore until ExpiresAt when either is set. The members
+// of SubjectSets are bound along with Subjects.
 type RBACBinding struct {
 	Name                string               `json:"name"`
 	Subjects            []Subject            `json:"subjects"`
+	SubjectSets         []string             `json:"subjectSets,omitempty"`
 	ClusterRoleBindings []ClusterRoleBinding `json:"clusterRoleBindings"`
 	RoleBindings        []RoleBinding        `json:"roleBindings"`
+	NotBefore           *metav1.Time         `json:"notBefore,omitempty"`
+	ExpiresAt           *metav1.Time         `json:"expiresAt,omitempty"`
 }
 
 // ClusterRoleBinding is a specification for a ClusterRoleBinding resource
 type ClusterRoleBinding struct {
-	ClusterRole string `json:"clusterRole"`
+	ClusterRole string       `json:"clusterRole"`
+	NotBefore   *metav1.Time `json:"notBefore,omitempty"`
+	ExpiresAt   *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // RoleBinding is a specification for a RoleBinding resource
 type RoleBinding struct {
-	ClusterRole       string               `json:"clusterRole,omitempty"`
-	Role              string               `json:"role,omitempty"`
-	Namespace         string               `json:"namespace,omitempty"`
-	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	ClusterRole       string                 `json:"clusterRole,omitempty"`
+	Role              string                 `json:"role,omitempty"`
+	Namespace         string                 `json:"namespace,omitempty"`
+	NamespaceSelector metav1.LabelSelector   `json:"namespaceSelector,omitempty"`
+	NamespaceNames    *NamespaceNameSelector `json:"namespaceNames,omitempty"`
+	NotBefore         *metav1.Time           `json:"notBefore,omitempty"`
+	ExpiresAt         *metav1.Time           `json:"expiresAt,omitempty"`
+	RoleTemplate      *RoleTemplate          `json:"roleTemplate,omitempty"`
+}
+
+// RoleTemplate defines the rules of the Role named by a RoleBinding. The Role
+// is created in every namespace the RoleBinding is created in.
+type RoleTemplate struct {
+	Rules []rbacv1.PolicyRule `json:"rules"`
+}
+
+// ClusterRole is a specification for a ClusterRole resource. Labels are added
+// to the ClusterRole, e.g. to aggregate it into the built-in ClusterRoles.
+type ClusterRole struct {
+	Name            string                  `json:"name"`
+	Labels          map[string]string       `json:"labels,omitempty"`
+	Rules           []rbacv1.PolicyRule     `json:"rules,omitempty"`
+	AggregationRule *rbacv1.AggregationRule `json:"aggregationRule,omitempty"`
+}
+
+// Role is a specification for a Role resource
+type Role struct {
+	Name      string              `json:"name"`
+	Namespace string              `json:"namespace"`
+	Rules     []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+// Pattern types supported by a NamespaceNameSelector
+const (
+	// NamespaceNameGlob patterns support * and ? wildcards and [] character classes
+	NamespaceNameGlob = "Glob"
+	// NamespaceNameRegex patterns are regular expressions that must match the whole name
+	NamespaceNameRegex = "Regex"
+)
+
+// NamespaceNameSelector selects namespaces by name. A namespace is selected if
+// it matches any Include pattern, or Include is empty, and no Exclude pattern.
+type NamespaceNameSelector struct {
+	Type    string   `json:"type,omitempty"`
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
 }
 
 // +genclient
@@ -58,11 +121,70 @@ type RBACDefinition struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 	RBACBindings      []RBACBinding        `json:"rbacBindings"`
+	ClusterRoles      []ClusterRole        `json:"clusterRoles,omitempty"`
+	Roles             []Role               `json:"roles,omitempty"`
 	Status            RBACDefinitionStatus `json:"status,omitempty"`
 }
 
-// RBACDefinitionStatus defines the observed state of RBACDefinition
+// Condition types reported on RBACDefinitionStatus and RBACBindingStatus
+const (
+	// ConditionReady indicates that all requested resources are in place
+	ConditionReady = "Ready"
+	// ConditionDegraded indicates that some requested resources could not be created or deleted
+	ConditionDegraded = "Degraded"
+	// ConditionInvalidSpec indicates that the RBAC Definition could not be parsed
+	ConditionInvalidSpec = "InvalidSpec"
+	// ConditionActive indicates that an RBACBinding is within its notBefore and expiresAt times
+	ConditionActive = "Active"
+	// ConditionPolicyViolation indicates that some requested resources are forbidden by an RBACManagerPolicy
+	ConditionPolicyViolation = "PolicyViolation"
+)
+
+// RBACDefinitionStatus defines the observed state of RBACDefinition. Adopted
+// lists the existing resources the last reconciliation took ownership of.
 type RBACDefinitionStatus struct {
+	ObservedGeneration  int64               `json:"observedGeneration,omitempty"`
+	LastReconcileTime   *metav1.Time        `json:"lastReconcileTime,omitempty"`
+	Managed             ManagedObjectCounts `json:"managed,omitempty"`
+	Conditions          []metav1.Condition  `json:"conditions,omitempty"`
+	RBACBindings        []RBACBindingStatus `json:"rbacBindings,omitempty"`
+	Plan                *Plan               `json:"plan,omitempty"`
+	NextScheduledChange *metav1.Time        `json:"nextScheduledChange,omitempty"`
+	PendingApprovals    []PlannedChange     `json:"pendingApprovals,omitempty"`
+	Adopted             []PlannedChange     `json:"adopted,omitempty"`
+}
+
+// ManagedObjectCounts is the number of each kind of resource managed by an RBAC Definition
+type ManagedObjectCounts struct {
+	ClusterRoleBindings int `json:"clusterRoleBindings"`
+	RoleBindings        int `json:"roleBindings"`
+	ServiceAccounts     int `json:"serviceAccounts"`
+	ClusterRoles        int `json:"clusterRoles,omitempty"`
+	Roles               int `json:"roles,omitempty"`
+	Secrets             int `json:"secrets,omitempty"`
+}
+
+// RBACBindingStatus defines the observed state of a single RBACBinding
+type RBACBindingStatus struct {
+	Name              string             `json:"name"`
+	Conditions        []metav1.Condition `json:"conditions,omitempty"`
+	SkippedNamespaces []string           `json:"skippedNamespaces,omitempty"`
+}
+
+// Plan describes the changes RBAC Manager would make to reconcile an RBAC Definition
+type Plan struct {
+	Create []PlannedChange `json:"create,omitempty"`
+	Update []PlannedChange `json:"update,omitempty"`
+	Delete []PlannedChange `json:"delete,omitempty"`
+}
+
+// PlannedChange identifies a Kubernetes resource that RBAC Manager would change
+type PlannedChange struct {
+	Kind      string           `json:"kind"`
+	Name      string           `json:"name"`
+	Namespace string           `json:"namespace,omitempty"`
+	RoleRef   *rbacv1.RoleRef  `json:"roleRef,omitempty"`
+	Subjects  []rbacv1.Subject `json:"subjects,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
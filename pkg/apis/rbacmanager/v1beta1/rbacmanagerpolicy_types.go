@@ -0,0 +1,59 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RBACManagerPolicy limits what every RBAC Definition in the cluster may do.
+// When more than one policy exists, all of them apply.
+// +k8s:openapi-gen=true
+type RBACManagerPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// AllowedClusterRoles are the only ClusterRoles that may be bound, when set
+	AllowedClusterRoles []string `json:"allowedClusterRoles,omitempty"`
+	// DeniedClusterRoles may never be bound
+	DeniedClusterRoles []string `json:"deniedClusterRoles,omitempty"`
+	// ForbiddenSubjects may never be bound, a subject without a namespace matches every namespace
+	ForbiddenSubjects []rbacv1.Subject `json:"forbiddenSubjects,omitempty"`
+	// ForbiddenNamespaces may never contain Role Bindings or Service Accounts
+	ForbiddenNamespaces []string `json:"forbiddenNamespaces,omitempty"`
+	// DeniedRules may not be granted by the Roles, ClusterRoles, and role templates an RBAC Definition defines
+	DeniedRules []rbacv1.PolicyRule `json:"deniedRules,omitempty"`
+	// MaxSelectedNamespaces limits the number of namespaces a single Role Binding may select
+	MaxSelectedNamespaces *int `json:"maxSelectedNamespaces,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RBACManagerPolicyList contains a list of RBACManagerPolicy
+type RBACManagerPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RBACManagerPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RBACManagerPolicy{}, &RBACManagerPolicyList{})
+}
@@ -15,32 +15,45 @@ package controller
 
 import (
 	"context"
+	"log/slog"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
-	"github.com/fairwindsops/rbac-manager/pkg/kube"
 	"github.com/fairwindsops/rbac-manager/pkg/metrics"
 	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
 )
 
 // newNamespaceReconciler returns a new reconcile.Reconciler
-func newNamespaceReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileNamespace{Client: mgr.GetClient(), config: mgr.GetConfig(), scheme: mgr.GetScheme()}
+func newNamespaceReconciler(mgr manager.Manager, options reconciler.Options) reconcile.Reconciler {
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+
+	if err != nil {
+		// If we can't get a clientset we can't do anything else
+		panic(err)
+	}
+
+	return &ReconcileNamespace{
+		Client:    mgr.GetClient(),
+		clientset: clientset,
+		scheme:    mgr.GetScheme(),
+		options:   options,
+	}
 }
 
 // ReconcileNamespace reconciles a Namespace object
 type ReconcileNamespace struct {
 	client.Client
-	scheme *runtime.Scheme
-	config *rest.Config
+	scheme    *runtime.Scheme
+	clientset kubernetes.Interface
+	options   reconciler.Options
 }
 
 // Reconcile makes changes in response to Namespace changes
@@ -53,19 +66,19 @@ func (r *ReconcileNamespace) Reconcile(ctx context.Context, request reconcile.Re
 
 	if err != nil {
 		if errors.IsNotFound(err) {
-			err = reconcileNamespace(r.config, namespace)
+			err = r.reconcileNamespace(ctx, namespace)
 			if err != nil {
-				metrics.ErrorCounter.Inc()
+				metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 				return reconcile.Result{}, err
 			}
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
-		metrics.ErrorCounter.Inc()
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 		return reconcile.Result{}, err
 	}
 
-	err = reconcileNamespace(r.config, namespace)
+	err = r.reconcileNamespace(ctx, namespace)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -73,31 +86,25 @@ func (r *ReconcileNamespace) Reconcile(ctx context.Context, request reconcile.Re
 	return reconcile.Result{}, nil
 }
 
-func reconcileNamespace(config *rest.Config, namespace *v1.Namespace) error {
+func (r *ReconcileNamespace) reconcileNamespace(ctx context.Context, namespace *v1.Namespace) error {
 	metrics.ReconcileCounter.WithLabelValues("namespace").Inc()
-	var err error
-	var rbacDefList rbacmanagerv1beta1.RBACDefinitionList
-	rdr := reconciler.Reconciler{}
-
-	// Full Kubernetes ClientSet is required because RBAC types don't
-	//   implement methods required for controller-runtime methods to work
-	rdr.Clientset, err = kubernetes.NewForConfig(config)
-
-	if err != nil {
-		return err
-	}
+	rdr := reconciler.Reconciler{Options: r.options, Clientset: r.clientset, Reader: r.Client}
 
-	rbacDefList, err = kube.GetRbacDefinitions()
+	rbacDefList := &rbacmanagerv1beta1.RBACDefinitionList{}
+	err := r.List(ctx, rbacDefList)
 	if err != nil {
 		return err
 	}
 
+	// An RBAC Definition that can not be reconciled does not stop the rest
+	errs := []error{}
 	for _, rbacDef := range rbacDefList.Items {
 		err = rdr.ReconcileNamespaceChange(&rbacDef, namespace)
 		if err != nil {
-			return err
+			slog.Error("Error reconciling namespace", "namespace", namespace.Name, "rbacDefinition", rbacDef.Name, "error", err)
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
 }
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/metrics"
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+)
+
+// newNamespacedRbacDefReconciler returns a new reconcile.Reconciler
+func newNamespacedRbacDefReconciler(mgr manager.Manager, options reconciler.Options) reconcile.Reconciler {
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+
+	if err != nil {
+		// If we can't get a clientset we can't do anything else
+		panic(err)
+	}
+
+	return &ReconcileNamespacedRBACDefinition{
+		Client:    mgr.GetClient(),
+		clientset: clientset,
+		scheme:    mgr.GetScheme(),
+		options:   options,
+	}
+}
+
+// ReconcileNamespacedRBACDefinition reconciles a NamespacedRBACDefinition object
+type ReconcileNamespacedRBACDefinition struct {
+	client.Client
+	scheme    *runtime.Scheme
+	clientset kubernetes.Interface
+	options   reconciler.Options
+}
+
+// Reconcile makes changes in response to NamespacedRBACDefinition changes
+func (r *ReconcileNamespacedRBACDefinition) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	metrics.ReconcileCounter.WithLabelValues("namespacedrbacdefinition").Inc()
+	observeWatchLag(request)
+	var err error
+	rdr := reconciler.Reconciler{Options: r.options, Clientset: r.clientset, Reader: r.Client}
+
+	// Fetch the NamespacedRBACDefinition instance
+	nrd := &rbacmanagerv1beta1.NamespacedRBACDefinition{}
+	err = r.Get(ctx, request.NamespacedName, nrd)
+	if err != nil {
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		if errors.IsNotFound(err) {
+			// Object not found, return.  Created objects are automatically garbage collected.
+			metrics.InactiveBindingsGauge.DeletePartialMatch(prometheus.Labels{"namespace": request.Namespace, "rbacdefinition": request.Name})
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	err = rdr.ReconcileNamespaced(nrd)
+	if err != nil {
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+	}
+
+	statusErr := r.Status().Update(ctx, nrd)
+	if statusErr != nil {
+		slog.Error("Error updating NamespacedRBACDefinition status", "name", nrd.Name, "namespace", nrd.Namespace, "error", statusErr)
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+	}
+
+	// An invalid spec is recorded on the status instead of being returned,
+	// so only errors that are worth retrying are returned here
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Reconcile again when a binding becomes active or expires
+	result := reconcile.Result{}
+	if next := nrd.Status.NextScheduledChange; next != nil {
+		result.RequeueAfter = time.Until(next.Time)
+	}
+
+	return result, statusErr
+}
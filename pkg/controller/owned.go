@@ -0,0 +1,151 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/fairwindsops/rbac-manager/pkg/kube"
+	"github.com/fairwindsops/rbac-manager/pkg/metrics"
+)
+
+// ownedResources are the kinds of resources RBAC Definitions create, keyed by
+//
+//	the resource label of the watch lag metric
+var ownedResources = map[string]client.Object{
+	"clusterrolebindings": &rbacv1.ClusterRoleBinding{},
+	"rolebindings":        &rbacv1.RoleBinding{},
+	"clusterroles":        &rbacv1.ClusterRole{},
+	"roles":               &rbacv1.Role{},
+	"serviceaccounts":     &corev1.ServiceAccount{},
+	"secrets":             &corev1.Secret{},
+}
+
+// ownedPredicate ignores resources that are not managed by RBAC Manager and
+//
+//	the creation of resources, which the owner has just reconciled
+var ownedPredicate = predicate.And(
+	predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetLabels()[kube.LabelKey] == kube.LabelValue
+	}),
+	predicate.Funcs{CreateFunc: func(event.CreateEvent) bool { return false }},
+)
+
+// watchOwned reconciles the owner of a resource created for an owner of type
+//
+//	ownerType with c whenever the resource is modified or deleted by someone else
+func watchOwned(mgr manager.Manager, c controller.Controller, ownerType client.Object) error {
+	for resource, object := range ownedResources {
+		h := &queuedChangeHandler{
+			EventHandler: handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), ownerType),
+			resource:     resource,
+		}
+		err := c.Watch(source.Kind(mgr.GetCache(), object, handler.EventHandler(h), ownedPredicate))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queuedChanges remembers when a change to an owned resource first queued a
+//
+//	reconcile of its owner, so the lag until that reconcile starts can be measured
+var queuedChanges = struct {
+	sync.Mutex
+	requests map[reconcile.Request]queuedChange
+}{requests: map[reconcile.Request]queuedChange{}}
+
+type queuedChange struct {
+	resource string
+	queuedAt time.Time
+}
+
+// observeWatchLag records the time since a change to a resource owned by the
+//
+//	object of request queued the reconcile that is now starting
+func observeWatchLag(request reconcile.Request) {
+	queuedChanges.Lock()
+	change, ok := queuedChanges.requests[request]
+	delete(queuedChanges.requests, request)
+	queuedChanges.Unlock()
+
+	if ok {
+		metrics.WatchLagHistogram.WithLabelValues(change.resource).Observe(time.Since(change.queuedAt).Seconds())
+	}
+}
+
+// queuedChangeHandler wraps an event handler to note when it queues requests
+type queuedChangeHandler struct {
+	handler.EventHandler
+	resource string
+}
+
+func (h *queuedChangeHandler) Create(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.EventHandler.Create(ctx, e, &queuedChangeQueue{TypedRateLimitingInterface: q, resource: h.resource})
+}
+
+func (h *queuedChangeHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.EventHandler.Update(ctx, e, &queuedChangeQueue{TypedRateLimitingInterface: q, resource: h.resource})
+}
+
+func (h *queuedChangeHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.EventHandler.Delete(ctx, e, &queuedChangeQueue{TypedRateLimitingInterface: q, resource: h.resource})
+}
+
+func (h *queuedChangeHandler) Generic(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.EventHandler.Generic(ctx, e, &queuedChangeQueue{TypedRateLimitingInterface: q, resource: h.resource})
+}
+
+type queuedChangeQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	resource string
+}
+
+func (q *queuedChangeQueue) Add(request reconcile.Request) {
+	queuedChanges.Lock()
+	if _, ok := queuedChanges.requests[request]; !ok {
+		queuedChanges.requests[request] = queuedChange{resource: q.resource, queuedAt: time.Now()}
+	}
+	queuedChanges.Unlock()
+
+	q.TypedRateLimitingInterface.Add(request)
+}
+
+// WatchErrorHandler counts every time a watch of the manager cache is dropped
+//
+//	and has to be restarted, then logs the error like the default handler
+func WatchErrorHandler(ctx context.Context, r *toolscache.Reflector, err error) {
+	metrics.WatchRestartCounter.WithLabelValues(r.TypeDescription()).Inc()
+	toolscache.DefaultWatchErrorHandler(ctx, r, err)
+}
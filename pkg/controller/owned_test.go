@@ -0,0 +1,84 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/fairwindsops/rbac-manager/pkg/kube"
+	"github.com/fairwindsops/rbac-manager/pkg/metrics"
+)
+
+func TestOwnedPredicate(t *testing.T) {
+	managed := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "managed", Labels: kube.Labels}}
+	unmanaged := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}}
+
+	assert.True(t, ownedPredicate.Update(event.UpdateEvent{ObjectOld: managed, ObjectNew: managed}))
+	assert.True(t, ownedPredicate.Delete(event.DeleteEvent{Object: managed}))
+	assert.False(t, ownedPredicate.Create(event.CreateEvent{Object: managed}))
+
+	assert.False(t, ownedPredicate.Update(event.UpdateEvent{ObjectOld: unmanaged, ObjectNew: unmanaged}))
+	assert.False(t, ownedPredicate.Delete(event.DeleteEvent{Object: unmanaged}))
+	assert.False(t, ownedPredicate.Create(event.CreateEvent{Object: unmanaged}))
+}
+
+func TestObserveWatchLag(t *testing.T) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "lag-example"}}
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+
+	samples := func() uint64 {
+		metric := &dto.Metric{}
+		err := metrics.WatchLagHistogram.WithLabelValues("rolebindings").(prometheus.Histogram).Write(metric)
+		assert.NoError(t, err)
+		return metric.GetHistogram().GetSampleCount()
+	}
+	before := samples()
+
+	// only the first change queued before the reconcile starts is remembered
+	q := &queuedChangeQueue{TypedRateLimitingInterface: queue, resource: "rolebindings"}
+	q.Add(request)
+	queuedChanges.Lock()
+	queuedAt := queuedChanges.requests[request].queuedAt
+	queuedChanges.Unlock()
+	q.Add(request)
+
+	queuedChanges.Lock()
+	assert.Equal(t, queuedChange{resource: "rolebindings", queuedAt: queuedAt}, queuedChanges.requests[request])
+	queuedChanges.Unlock()
+	assert.Equal(t, 1, queue.Len())
+
+	observeWatchLag(request)
+	assert.Equal(t, before+1, samples())
+	queuedChanges.Lock()
+	assert.NotContains(t, queuedChanges.requests, request)
+	queuedChanges.Unlock()
+
+	// reconciles that were not queued by a change are not observed
+	observeWatchLag(request)
+	assert.Equal(t, before+1, samples())
+}
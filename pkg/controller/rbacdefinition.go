@@ -18,7 +18,10 @@ package controller
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -32,7 +35,7 @@ import (
 )
 
 // newRbacDefReconciler returns a new reconcile.Reconciler
-func newRbacDefReconciler(mgr manager.Manager) reconcile.Reconciler {
+func newRbacDefReconciler(mgr manager.Manager, options reconciler.Options) reconcile.Reconciler {
 	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
 
 	if err != nil {
@@ -44,6 +47,7 @@ func newRbacDefReconciler(mgr manager.Manager) reconcile.Reconciler {
 		Client:    mgr.GetClient(),
 		clientset: clientset,
 		scheme:    mgr.GetScheme(),
+		options:   options,
 	}
 }
 
@@ -52,21 +56,24 @@ type ReconcileRBACDefinition struct {
 	client.Client
 	scheme    *runtime.Scheme
 	clientset kubernetes.Interface
+	options   reconciler.Options
 }
 
 // Reconcile makes changes in response to RBACDefinition changes
 func (r *ReconcileRBACDefinition) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	metrics.ReconcileCounter.WithLabelValues("rbacdefinition").Inc()
+	observeWatchLag(request)
 	var err error
-	rdr := reconciler.Reconciler{Clientset: r.clientset}
+	rdr := reconciler.Reconciler{Options: r.options, Clientset: r.clientset, Reader: r.Client}
 
 	// Fetch the RBACDefinition instance
 	rbacDef := &rbacmanagerv1beta1.RBACDefinition{}
 	err = r.Get(ctx, request.NamespacedName, rbacDef)
 	if err != nil {
-		metrics.ErrorCounter.Inc()
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
+			metrics.InactiveBindingsGauge.DeletePartialMatch(prometheus.Labels{"namespace": request.Namespace, "rbacdefinition": request.Name})
 			// For additional cleanup logic use finalizers.
 			return reconcile.Result{}, nil
 		}
@@ -76,9 +83,26 @@ func (r *ReconcileRBACDefinition) Reconcile(ctx context.Context, request reconci
 
 	err = rdr.Reconcile(rbacDef)
 	if err != nil {
-		metrics.ErrorCounter.Inc()
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+	}
+
+	statusErr := r.Status().Update(ctx, rbacDef)
+	if statusErr != nil {
+		slog.Error("Error updating RBACDefinition status", "name", rbacDef.Name, "error", statusErr)
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+	}
+
+	// An invalid spec is recorded on the status instead of being returned,
+	// so only errors that are worth retrying are returned here
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	// Reconcile again when a binding becomes active or expires
+	result := reconcile.Result{}
+	if next := rbacDef.Status.NextScheduledChange; next != nil {
+		result.RequeueAfter = time.Until(next.Time)
+	}
+
+	return result, statusErr
 }
@@ -17,34 +17,81 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"log/slog"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
 )
 
 // Add creates a new RBACDefinition Controller and adds it to the Manager.
-// The Manager will set fields on the Controller and Start it.
-func Add(mgr manager.Manager) error {
+// The Manager will set fields on the Controller and Start it. Every
+// Reconciler is created with options.
+func Add(mgr manager.Manager, options reconciler.Options) error {
 	var err error
 
+	// Status updates don't change the generation, this avoids reconciling our own writes
+	//   while still picking up changes to annotations like plan-only
 	rbacDef := &rbacmanagerv1beta1.RBACDefinition{}
-	err = addController(mgr, newRbacDefReconciler(mgr), "rbacdefinition", rbacDef)
+	c, err := addController(mgr, newRbacDefReconciler(mgr, options), "rbacdefinition", rbacDef,
+		predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))
+
+	if err == nil {
+		err = watchOwned(mgr, c, rbacDef)
+	}
+
+	if err == nil {
+		err = watchPolicies(mgr, c, &rbacmanagerv1beta1.RBACDefinitionList{})
+	}
+
+	if err == nil {
+		err = watchSubjectSets(mgr, c)
+	}
+
+	if err == nil {
+		err = watchConfigMaps(mgr, c, &rbacmanagerv1beta1.RBACDefinitionList{})
+	}
 
 	if err != nil {
 		slog.Error("Error adding RBAC Definition reconciler", "error", err)
 		return err
 	}
 
+	nrd := &rbacmanagerv1beta1.NamespacedRBACDefinition{}
+	c, err = addController(mgr, newNamespacedRbacDefReconciler(mgr, options), "namespacedrbacdefinition", nrd,
+		predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))
+
+	if err == nil {
+		err = watchOwned(mgr, c, nrd)
+	}
+
+	if err == nil {
+		err = watchPolicies(mgr, c, &rbacmanagerv1beta1.NamespacedRBACDefinitionList{})
+	}
+
+	if err == nil {
+		err = watchConfigMaps(mgr, c, &rbacmanagerv1beta1.NamespacedRBACDefinitionList{})
+	}
+
+	if err != nil {
+		slog.Error("Error adding Namespaced RBAC Definition reconciler", "error", err)
+		return err
+	}
+
 	namespace := &corev1.Namespace{}
-	err = addController(mgr, newNamespaceReconciler(mgr), "namespace", namespace)
+	_, err = addController(mgr, newNamespaceReconciler(mgr, options), "namespace", namespace)
 
 	if err != nil {
 		slog.Error("Error adding Namespace reconciler", "error", err)
@@ -55,19 +102,109 @@ func Add(mgr manager.Manager) error {
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func addController(mgr manager.Manager, r reconcile.Reconciler, name string, cType client.Object) error {
+func addController(mgr manager.Manager, r reconcile.Reconciler, name string, cType client.Object, predicates ...predicate.Predicate) (controller.Controller, error) {
 	// Create a new controller
 	c, err := controller.New(name, mgr, controller.Options{Reconciler: r})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Watch for changes to Resource
-	err = c.Watch(source.Kind(mgr.GetCache(), cType, &handler.EnqueueRequestForObject{}))
+	err = c.Watch(source.Kind(mgr.GetCache(), cType, &handler.EnqueueRequestForObject{}, predicates...))
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return c, nil
+}
+
+// watchPolicies reconciles every object in list with c whenever an
+//
+//	RBACManagerPolicy changes, since any of them may now violate it
+func watchPolicies(mgr manager.Manager, c controller.Controller, list client.ObjectList) error {
+	var policy client.Object = &rbacmanagerv1beta1.RBACManagerPolicy{}
+	return c.Watch(source.Kind(mgr.GetCache(), policy, handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, _ client.Object) []reconcile.Request {
+			objects := list.DeepCopyObject().(client.ObjectList)
+			if err := mgr.GetClient().List(ctx, objects); err != nil {
+				slog.Error("Error listing objects affected by RBAC Manager policy", "error", err)
+				return nil
+			}
+
+			requests := []reconcile.Request{}
+			_ = meta.EachListItem(objects, func(object runtime.Object) error {
+				if o, ok := object.(client.Object); ok {
+					requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+				}
+				return nil
+			})
+			return requests
+		}),
+		predicate.GenerationChangedPredicate{}))
+}
+
+// watchSubjectSets reconciles the RBACDefinitions that refer to a SubjectSet,
+//
+//	directly or through other SubjectSets, whenever it changes
+func watchSubjectSets(mgr manager.Manager, c controller.Controller) error {
+	var subjectSet client.Object = &rbacmanagerv1beta1.SubjectSet{}
+	return c.Watch(source.Kind(mgr.GetCache(), subjectSet, handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, changed client.Object) []reconcile.Request {
+			rbacDefs := &rbacmanagerv1beta1.RBACDefinitionList{}
+			if err := mgr.GetClient().List(ctx, rbacDefs); err != nil {
+				slog.Error("Error listing RBAC Definitions affected by subject set", "error", err)
+				return nil
+			}
+			subjectSets := &rbacmanagerv1beta1.SubjectSetList{}
+			if err := mgr.GetClient().List(ctx, subjectSets); err != nil {
+				slog.Error("Error listing subject sets", "error", err)
+				return nil
+			}
+
+			requests := []reconcile.Request{}
+			for _, rbacDef := range rbacDefs.Items {
+				if reconciler.ReferencesSubjectSet(&rbacDef, changed.GetName(), subjectSets.Items) {
+					requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rbacDef)})
+				}
+			}
+			return requests
+		}),
+		predicate.GenerationChangedPredicate{}))
+}
+
+// watchConfigMaps reconciles every object in list with c that has subjects
+//
+//	from a ConfigMap whenever it changes. Only the metadata of ConfigMaps is
+//	cached, the Parser reads their data when it needs it.
+func watchConfigMaps(mgr manager.Manager, c controller.Controller, list client.ObjectList) error {
+	configMap := &metav1.PartialObjectMetadata{}
+	configMap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+
+	var object client.Object = configMap
+	return c.Watch(source.Kind(mgr.GetCache(), object, handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, changed client.Object) []reconcile.Request {
+			objects := list.DeepCopyObject().(client.ObjectList)
+			if err := mgr.GetClient().List(ctx, objects); err != nil {
+				slog.Error("Error listing objects affected by ConfigMap", "error", err)
+				return nil
+			}
+
+			key := client.ObjectKeyFromObject(changed)
+			requests := []reconcile.Request{}
+			_ = meta.EachListItem(objects, func(object runtime.Object) error {
+				var referenced bool
+				switch o := object.(type) {
+				case *rbacmanagerv1beta1.RBACDefinition:
+					referenced = reconciler.ReferencesConfigMap(o.RBACBindings, "", key)
+				case *rbacmanagerv1beta1.NamespacedRBACDefinition:
+					referenced = reconciler.ReferencesConfigMap(o.RBACBindings, o.Namespace, key)
+				}
+				if referenced {
+					requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(object.(client.Object))})
+				}
+				return nil
+			})
+			return requests
+		})))
 }
@@ -19,9 +19,12 @@ package kube
 import (
 	"context"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
@@ -41,16 +44,68 @@ func GetRbacDefinition(name string) (rbacmanagerv1beta1.RBACDefinition, error) {
 	return rbacDef, err
 }
 
-// GetRbacDefinitions returns an RbacDefinitionList or an error
-func GetRbacDefinitions() (rbacmanagerv1beta1.RBACDefinitionList, error) {
-	list := rbacmanagerv1beta1.RBACDefinitionList{}
+// GetRbacManagerPolicies returns an RBACManagerPolicyList or an error. The list
+//
+//	is empty when the RBACManagerPolicy CRD is not installed.
+func GetRbacManagerPolicies() (rbacmanagerv1beta1.RBACManagerPolicyList, error) {
+	list := rbacmanagerv1beta1.RBACManagerPolicyList{}
 
 	client, err := getRbacDefClient()
 	if err != nil {
 		return list, err
 	}
 
-	err = client.Get().Resource("rbacdefinitions").Do(context.TODO()).Into(&list)
+	err = client.Get().Resource("rbacmanagerpolicies").Do(context.TODO()).Into(&list)
+	if apierrors.IsNotFound(err) {
+		return list, nil
+	}
+
+	return list, err
+}
+
+// GetSubjectSets returns a SubjectSetList or an error. The list is empty when
+//
+//	the SubjectSet CRD is not installed.
+func GetSubjectSets() (rbacmanagerv1beta1.SubjectSetList, error) {
+	list := rbacmanagerv1beta1.SubjectSetList{}
+
+	client, err := getRbacDefClient()
+	if err != nil {
+		return list, err
+	}
+
+	err = client.Get().Resource("subjectsets").Do(context.TODO()).Into(&list)
+	if apierrors.IsNotFound(err) {
+		return list, nil
+	}
+
+	return list, err
+}
+
+// ListRbacManagerPolicies returns the RBACManagerPolicies known to reader or an
+//
+//	error. The list is empty when the RBACManagerPolicy CRD is not installed.
+func ListRbacManagerPolicies(reader client.Reader) (rbacmanagerv1beta1.RBACManagerPolicyList, error) {
+	list := rbacmanagerv1beta1.RBACManagerPolicyList{}
+
+	err := reader.List(context.TODO(), &list)
+	if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+		return list, nil
+	}
+
+	return list, err
+}
+
+// ListSubjectSets returns the SubjectSets known to reader or an error. The
+//
+//	list is empty when the SubjectSet CRD is not installed.
+func ListSubjectSets(reader client.Reader) (rbacmanagerv1beta1.SubjectSetList, error) {
+	list := rbacmanagerv1beta1.SubjectSetList{}
+
+	err := reader.List(context.TODO(), &list)
+	if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+		return list, nil
+	}
 
 	return list, err
 }
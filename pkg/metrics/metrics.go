@@ -22,25 +22,45 @@ import (
 
 const namespace = "rbacmanager"
 
+// Types of errors counted by ErrorCounter
+const (
+	// ErrorReconcile is an error while reconciling Kubernetes resources
+	ErrorReconcile = "reconcile"
+	// ErrorPolicyViolation is a requested resource that an RBACManagerPolicy forbids
+	ErrorPolicyViolation = "policy_violation"
+)
+
 var (
-	// ErrorCounter is a global counter for errors
-	ErrorCounter = prometheus.NewCounter(
+	// ErrorCounter is a global counter for errors by type
+	ErrorCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "errors_total",
 			Help:      "Number of errors while reconciling",
-		})
+		},
+		[]string{"type"},
+	)
 
 	// ChangeCounter counts kubernetes events (e.g. create, delete) on objects (e.g. ClusterRoleBinding)
 	ChangeCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "changed_total",
-			Help:      "Number of times a Kubernetes object is created or deleted by the rbac-manager",
+			Help:      "Number of times a Kubernetes object is created, updated or deleted by the rbac-manager",
 		},
 		[]string{"object", "action"},
 	)
 
+	// InactiveBindingsGauge tracks RBAC Bindings and their entries that are outside of their notBefore and expiresAt times
+	InactiveBindingsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "inactive_bindings",
+			Help:      "Number of RBAC Bindings and entries that are not yet active or have expired",
+		},
+		[]string{"namespace", "rbacdefinition", "reason"},
+	)
+
 	// ReconcileCounter counts controllers invocations
 	ReconcileCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -50,6 +70,27 @@ var (
 		},
 		[]string{"controller"},
 	)
+
+	// WatchRestartCounter counts watches of the manager cache that were dropped with an error and restarted
+	WatchRestartCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "watch_restarts_total",
+			Help:      "Number of times a watch was dropped with an error and restarted",
+		},
+		[]string{"type"},
+	)
+
+	// WatchLagHistogram tracks the time between a change to an owned resource and the reconcile of its owner
+	WatchLagHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "watch_lag_seconds",
+			Help:      "Seconds between a change to a resource owned by an RBAC Definition and the start of the reconcile that repairs it",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60},
+		},
+		[]string{"resource"},
+	)
 )
 
 // RegisterMetrics must be called exactly once and registers the prometheus counters as metrics
@@ -57,4 +98,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(ErrorCounter)
 	prometheus.MustRegister(ChangeCounter)
 	prometheus.MustRegister(ReconcileCounter)
+	prometheus.MustRegister(InactiveBindingsGauge)
+	prometheus.MustRegister(WatchRestartCounter)
+	prometheus.MustRegister(WatchLagHistogram)
 }
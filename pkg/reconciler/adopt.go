@@ -0,0 +1,69 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/metrics"
+)
+
+// AdoptAnnotationKey is the annotation that lets an RBAC Definition take ownership of existing resources with the same name
+const AdoptAnnotationKey string = "rbacmanager.reactiveops.io/adopt"
+
+// adoptOptions takes over the fields of an adopted resource from the field
+//
+//	manager that created it
+var adoptOptions = metav1.ApplyOptions{FieldManager: FieldManager, Force: true}
+
+// IsAdopting returns true if an RBAC Definition asks to adopt existing resources
+func IsAdopting(rbacDef *rbacmanagerv1beta1.RBACDefinition) bool {
+	adopt, _ := strconv.ParseBool(rbacDef.Annotations[AdoptAnnotationKey])
+	return adopt
+}
+
+// adoptable returns an error unless an existing resource that is not owned
+//
+//	by the RBAC Definition being reconciled can be adopted by it. Resources
+//	owned by anything else are never adopted.
+func (r *Reconciler) adoptable(resource schema.GroupResource, existing *metav1.ObjectMeta) error {
+	if !r.adopt {
+		return apierrors.NewAlreadyExists(resource, existing.Name)
+	}
+
+	if len(existing.OwnerReferences) > 0 {
+		owner := existing.OwnerReferences[0]
+		if controller := metav1.GetControllerOfNoCopy(existing); controller != nil {
+			owner = *controller
+		}
+		return fmt.Errorf("%v %v is owned by %v %v and can not be adopted", resource.Resource, existing.Name, owner.Kind, owner.Name)
+	}
+
+	return nil
+}
+
+// recordAdoption reports an existing resource that is now owned by the RBAC Definition
+func (r *Reconciler) recordAdoption(kind string, resource schema.GroupResource, objectMeta *metav1.ObjectMeta) {
+	slog.Info("Adopted existing resource", "kind", kind, "name", objectMeta.Name, "namespace", objectMeta.Namespace)
+	r.adopted = append(r.adopted, plannedChange(kind, objectMeta, nil, nil))
+	metrics.ChangeCounter.WithLabelValues(resource.Resource, "adopt").Inc()
+}
@@ -0,0 +1,349 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	rbacv1ac "k8s.io/client-go/applyconfigurations/rbac/v1"
+	"k8s.io/client-go/util/csaupgrade"
+)
+
+// FieldManager is the field manager rbac-manager uses for server-side apply
+const FieldManager string = "rbac-manager"
+
+// applyOptions leaves Force unset so fields owned by other field managers
+//
+//	are reported as conflicts instead of being overwritten
+var applyOptions = metav1.ApplyOptions{FieldManager: FieldManager}
+
+// legacyFieldManagers owned the fields of resources created before
+//
+//	rbac-manager used server-side apply
+var legacyFieldManagers = sets.New(FieldManager)
+
+func (r *Reconciler) applyClusterRoleBinding(crb *rbacv1.ClusterRoleBinding) error {
+	client := r.Clientset.RbacV1().ClusterRoleBindings()
+
+	resource := rbacv1.Resource("clusterrolebindings")
+	options := applyOptions
+	adopting := false
+
+	existing, err := getExisting(r, &rbacv1.ClusterRoleBinding{}, "", crb.Name, client.Get)
+	if err == nil {
+		if !ownerRefsMatch(&existing.OwnerReferences, &crb.OwnerReferences) {
+			err = r.adoptable(resource, &existing.ObjectMeta)
+			if err != nil {
+				return err
+			}
+			adopting = true
+			options = adoptOptions
+		}
+		err = upgradeManagedFields(existing, func(patch []byte) error {
+			_, err := client.Patch(context.TODO(), crb.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+			return err
+		})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = client.Apply(context.TODO(), clusterRoleBindingApplyConfiguration(crb), options)
+	if err == nil && adopting {
+		r.recordAdoption("ClusterRoleBinding", resource, &crb.ObjectMeta)
+	}
+	return err
+}
+
+func (r *Reconciler) applyRoleBinding(rb *rbacv1.RoleBinding) error {
+	client := r.Clientset.RbacV1().RoleBindings(rb.Namespace)
+
+	resource := rbacv1.Resource("rolebindings")
+	options := applyOptions
+	adopting := false
+
+	existing, err := getExisting(r, &rbacv1.RoleBinding{}, rb.Namespace, rb.Name, client.Get)
+	if err == nil {
+		if !ownerRefsMatch(&existing.OwnerReferences, &rb.OwnerReferences) {
+			err = r.adoptable(resource, &existing.ObjectMeta)
+			if err != nil {
+				return err
+			}
+			adopting = true
+			options = adoptOptions
+		}
+		err = upgradeManagedFields(existing, func(patch []byte) error {
+			_, err := client.Patch(context.TODO(), rb.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+			return err
+		})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = client.Apply(context.TODO(), roleBindingApplyConfiguration(rb), options)
+	if err == nil && adopting {
+		r.recordAdoption("RoleBinding", resource, &rb.ObjectMeta)
+	}
+	return err
+}
+
+func (r *Reconciler) applyClusterRole(cr *rbacv1.ClusterRole) error {
+	client := r.Clientset.RbacV1().ClusterRoles()
+
+	existing, err := getExisting(r, &rbacv1.ClusterRole{}, "", cr.Name, client.Get)
+	if err == nil {
+		if !ownerRefsMatch(&existing.OwnerReferences, &cr.OwnerReferences) {
+			return apierrors.NewAlreadyExists(rbacv1.Resource("clusterroles"), cr.Name)
+		}
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = client.Apply(context.TODO(), clusterRoleApplyConfiguration(cr), applyOptions)
+	return err
+}
+
+func (r *Reconciler) applyRole(role *rbacv1.Role) error {
+	client := r.Clientset.RbacV1().Roles(role.Namespace)
+
+	existing, err := getExisting(r, &rbacv1.Role{}, role.Namespace, role.Name, client.Get)
+	if err == nil {
+		if !ownerRefsMatch(&existing.OwnerReferences, &role.OwnerReferences) {
+			return apierrors.NewAlreadyExists(rbacv1.Resource("roles"), role.Name)
+		}
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = client.Apply(context.TODO(), roleApplyConfiguration(role), applyOptions)
+	return err
+}
+
+func (r *Reconciler) applyServiceAccount(sa *v1.ServiceAccount) error {
+	client := r.Clientset.CoreV1().ServiceAccounts(sa.Namespace)
+
+	resource := v1.Resource("serviceaccounts")
+	options := applyOptions
+	adopting := false
+
+	existing, err := getExisting(r, &v1.ServiceAccount{}, sa.Namespace, sa.Name, client.Get)
+	if err == nil {
+		if !ownerRefsMatch(&existing.OwnerReferences, &sa.OwnerReferences) {
+			err = r.adoptable(resource, &existing.ObjectMeta)
+			if err != nil {
+				return err
+			}
+			adopting = true
+			options = adoptOptions
+		}
+		err = upgradeManagedFields(existing, func(patch []byte) error {
+			_, err := client.Patch(context.TODO(), sa.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+			return err
+		})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = client.Apply(context.TODO(), serviceAccountApplyConfiguration(sa), options)
+	if err == nil && adopting {
+		r.recordAdoption("ServiceAccount", resource, &sa.ObjectMeta)
+	}
+	return err
+}
+
+func (r *Reconciler) applySecret(secret *v1.Secret) error {
+	client := r.Clientset.CoreV1().Secrets(secret.Namespace)
+
+	existing, err := getExisting(r, &v1.Secret{}, secret.Namespace, secret.Name, client.Get)
+	if err == nil {
+		if !ownerRefsMatch(&existing.OwnerReferences, &secret.OwnerReferences) {
+			return apierrors.NewAlreadyExists(v1.Resource("secrets"), secret.Name)
+		}
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = client.Apply(context.TODO(), secretApplyConfiguration(secret), applyOptions)
+	return err
+}
+
+// upgradeManagedFields hands fields owned by legacy update operations over
+//
+//	to server-side apply so they are not reported as conflicts
+func upgradeManagedFields(existing runtime.Object, patch func([]byte) error) error {
+	upgradePatch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, legacyFieldManagers, FieldManager)
+	if err != nil || upgradePatch == nil {
+		return err
+	}
+	return patch(upgradePatch)
+}
+
+func clusterRoleBindingApplyConfiguration(crb *rbacv1.ClusterRoleBinding) *rbacv1ac.ClusterRoleBindingApplyConfiguration {
+	return rbacv1ac.ClusterRoleBinding(crb.Name).
+		WithLabels(crb.Labels).
+		WithAnnotations(crb.Annotations).
+		WithOwnerReferences(ownerReferenceApplyConfigurations(crb.OwnerReferences)...).
+		WithRoleRef(roleRefApplyConfiguration(&crb.RoleRef)).
+		WithSubjects(subjectApplyConfigurations(crb.Subjects)...)
+}
+
+func roleBindingApplyConfiguration(rb *rbacv1.RoleBinding) *rbacv1ac.RoleBindingApplyConfiguration {
+	return rbacv1ac.RoleBinding(rb.Name, rb.Namespace).
+		WithLabels(rb.Labels).
+		WithAnnotations(rb.Annotations).
+		WithOwnerReferences(ownerReferenceApplyConfigurations(rb.OwnerReferences)...).
+		WithRoleRef(roleRefApplyConfiguration(&rb.RoleRef)).
+		WithSubjects(subjectApplyConfigurations(rb.Subjects)...)
+}
+
+// clusterRoleApplyConfiguration leaves the rules of aggregated Cluster Roles
+//
+//	to the controller manager
+func clusterRoleApplyConfiguration(cr *rbacv1.ClusterRole) *rbacv1ac.ClusterRoleApplyConfiguration {
+	ac := rbacv1ac.ClusterRole(cr.Name).
+		WithLabels(cr.Labels).
+		WithAnnotations(cr.Annotations).
+		WithOwnerReferences(ownerReferenceApplyConfigurations(cr.OwnerReferences)...)
+
+	if cr.AggregationRule != nil {
+		aggregationRule := rbacv1ac.AggregationRule()
+		for _, selector := range cr.AggregationRule.ClusterRoleSelectors {
+			aggregationRule.WithClusterRoleSelectors(labelSelectorApplyConfiguration(&selector))
+		}
+		return ac.WithAggregationRule(aggregationRule)
+	}
+
+	return ac.WithRules(policyRuleApplyConfigurations(cr.Rules)...)
+}
+
+func roleApplyConfiguration(role *rbacv1.Role) *rbacv1ac.RoleApplyConfiguration {
+	return rbacv1ac.Role(role.Name, role.Namespace).
+		WithLabels(role.Labels).
+		WithAnnotations(role.Annotations).
+		WithOwnerReferences(ownerReferenceApplyConfigurations(role.OwnerReferences)...).
+		WithRules(policyRuleApplyConfigurations(role.Rules)...)
+}
+
+func serviceAccountApplyConfiguration(sa *v1.ServiceAccount) *corev1ac.ServiceAccountApplyConfiguration {
+	ac := corev1ac.ServiceAccount(sa.Name, sa.Namespace).
+		WithLabels(sa.Labels).
+		WithAnnotations(sa.Annotations).
+		WithOwnerReferences(ownerReferenceApplyConfigurations(sa.OwnerReferences)...)
+
+	for _, pullSecret := range sa.ImagePullSecrets {
+		ac.WithImagePullSecrets(corev1ac.LocalObjectReference().WithName(pullSecret.Name))
+	}
+
+	if sa.AutomountServiceAccountToken != nil {
+		ac.WithAutomountServiceAccountToken(*sa.AutomountServiceAccountToken)
+	}
+
+	return ac
+}
+
+func secretApplyConfiguration(secret *v1.Secret) *corev1ac.SecretApplyConfiguration {
+	return corev1ac.Secret(secret.Name, secret.Namespace).
+		WithType(secret.Type).
+		WithLabels(secret.Labels).
+		WithAnnotations(secret.Annotations).
+		WithOwnerReferences(ownerReferenceApplyConfigurations(secret.OwnerReferences)...)
+}
+
+func ownerReferenceApplyConfigurations(ownerRefs []metav1.OwnerReference) []*metav1ac.OwnerReferenceApplyConfiguration {
+	acs := []*metav1ac.OwnerReferenceApplyConfiguration{}
+	for _, ownerRef := range ownerRefs {
+		ac := metav1ac.OwnerReference().
+			WithAPIVersion(ownerRef.APIVersion).
+			WithKind(ownerRef.Kind).
+			WithName(ownerRef.Name).
+			WithUID(ownerRef.UID)
+		if ownerRef.Controller != nil {
+			ac.WithController(*ownerRef.Controller)
+		}
+		if ownerRef.BlockOwnerDeletion != nil {
+			ac.WithBlockOwnerDeletion(*ownerRef.BlockOwnerDeletion)
+		}
+		acs = append(acs, ac)
+	}
+	return acs
+}
+
+func roleRefApplyConfiguration(roleRef *rbacv1.RoleRef) *rbacv1ac.RoleRefApplyConfiguration {
+	ac := rbacv1ac.RoleRef().
+		WithKind(roleRef.Kind).
+		WithName(roleRef.Name)
+	if roleRef.APIGroup != "" {
+		ac.WithAPIGroup(roleRef.APIGroup)
+	}
+	return ac
+}
+
+func policyRuleApplyConfigurations(rules []rbacv1.PolicyRule) []*rbacv1ac.PolicyRuleApplyConfiguration {
+	acs := []*rbacv1ac.PolicyRuleApplyConfiguration{}
+	for _, rule := range rules {
+		acs = append(acs, rbacv1ac.PolicyRule().
+			WithVerbs(rule.Verbs...).
+			WithAPIGroups(rule.APIGroups...).
+			WithResources(rule.Resources...).
+			WithResourceNames(rule.ResourceNames...).
+			WithNonResourceURLs(rule.NonResourceURLs...))
+	}
+	return acs
+}
+
+func labelSelectorApplyConfiguration(selector *metav1.LabelSelector) *metav1ac.LabelSelectorApplyConfiguration {
+	ac := metav1ac.LabelSelector()
+	if selector.MatchLabels != nil {
+		ac.WithMatchLabels(selector.MatchLabels)
+	}
+	for _, requirement := range selector.MatchExpressions {
+		ac.WithMatchExpressions(metav1ac.LabelSelectorRequirement().
+			WithKey(requirement.Key).
+			WithOperator(requirement.Operator).
+			WithValues(requirement.Values...))
+	}
+	return ac
+}
+
+func subjectApplyConfigurations(subjects []rbacv1.Subject) []*rbacv1ac.SubjectApplyConfiguration {
+	acs := []*rbacv1ac.SubjectApplyConfiguration{}
+	for _, subject := range subjects {
+		ac := rbacv1ac.Subject().
+			WithKind(subject.Kind).
+			WithName(subject.Name)
+		if subject.APIGroup != "" {
+			ac.WithAPIGroup(subject.APIGroup)
+		}
+		if subject.Namespace != "" {
+			ac.WithNamespace(subject.Namespace)
+		}
+		acs = append(acs, ac)
+	}
+	return acs
+}
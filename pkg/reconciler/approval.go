@@ -0,0 +1,82 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RequestedByAnnotationKey records the user that last changed the rbacBindings of an RBAC Definition
+const RequestedByAnnotationKey string = "rbacmanager.reactiveops.io/requested-by"
+
+// ApprovedByAnnotationKey records the user that approved the current rbacBindings of an RBAC Definition
+const ApprovedByAnnotationKey string = "rbacmanager.reactiveops.io/approved-by"
+
+// IsApproved returns true if the rbacBindings of an RBAC Definition have been
+//
+//	approved by a different user than the one that requested them
+func IsApproved(objectMeta *metav1.ObjectMeta) bool {
+	requestedBy := objectMeta.Annotations[RequestedByAnnotationKey]
+	approvedBy := objectMeta.Annotations[ApprovedByAnnotationKey]
+	return requestedBy != "" && approvedBy != "" && approvedBy != requestedBy
+}
+
+// RecordApproval sets the approval annotations of an RBAC Definition for a
+//
+//	change made by username. A change to what it grants records the user
+//	that requested it and clears any previous approval. Otherwise a user that
+//	adds or changes the approval annotation is recorded as the approver.
+//	Existing values can't be changed by hand, oldAnnotations is nil on create.
+func RecordApproval(annotations, oldAnnotations map[string]string, specChanged bool, username string) {
+	if specChanged {
+		annotations[RequestedByAnnotationKey] = username
+		delete(annotations, ApprovedByAnnotationKey)
+		return
+	}
+
+	if requestedBy, ok := oldAnnotations[RequestedByAnnotationKey]; ok {
+		annotations[RequestedByAnnotationKey] = requestedBy
+	} else {
+		delete(annotations, RequestedByAnnotationKey)
+	}
+
+	approvedBy, ok := annotations[ApprovedByAnnotationKey]
+	if ok && approvedBy != oldAnnotations[ApprovedByAnnotationKey] {
+		annotations[ApprovedByAnnotationKey] = username
+	}
+}
+
+// requiresApproval returns true if a binding to roleRef has to wait for approval
+func (p *Parser) requiresApproval(roleRef *rbacv1.RoleRef) bool {
+	return !p.approved && roleRef.Kind == "ClusterRole" && p.ApprovalRequiredClusterRoles.Has(roleRef.Name)
+}
+
+// recordPendingApproval tracks a binding that is held back until it is approved
+func (r *Reconciler) recordPendingApproval(kind string, objectMeta *metav1.ObjectMeta, roleRef *rbacv1.RoleRef, subjects []rbacv1.Subject) {
+	r.pending = append(r.pending, plannedChange(kind, objectMeta, roleRef, subjects))
+}
+
+// pendingApprovalsByBinding counts the bindings held back for each RBACBinding
+func (r *Reconciler) pendingApprovalsByBinding(p *Parser) map[string]int {
+	counts := map[string]int{}
+	for _, pending := range r.pending {
+		objectMeta := metav1.ObjectMeta{Name: pending.Name, Namespace: pending.Namespace}
+		if bindingName, ok := p.bindingNames[objectKey(pending.Kind, &objectMeta)]; ok {
+			counts[bindingName]++
+		}
+	}
+	return counts
+}
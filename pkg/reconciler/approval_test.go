@@ -0,0 +1,74 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var recordApprovalTestCases = []struct {
+	name           string
+	annotations    map[string]string
+	oldAnnotations map[string]string
+	specChanged    bool
+	expected       map[string]string
+}{
+	{
+		"New RBAC Definition",
+		map[string]string{ApprovedByAnnotationKey: "sue"},
+		nil,
+		true,
+		map[string]string{RequestedByAnnotationKey: "joe"},
+	},
+	{
+		"Changed rbacBindings",
+		map[string]string{RequestedByAnnotationKey: "sue", ApprovedByAnnotationKey: "dave"},
+		map[string]string{RequestedByAnnotationKey: "sue", ApprovedByAnnotationKey: "dave"},
+		true,
+		map[string]string{RequestedByAnnotationKey: "joe"},
+	},
+	{
+		"Approval",
+		map[string]string{RequestedByAnnotationKey: "sue", ApprovedByAnnotationKey: "me"},
+		map[string]string{RequestedByAnnotationKey: "sue"},
+		false,
+		map[string]string{RequestedByAnnotationKey: "sue", ApprovedByAnnotationKey: "joe"},
+	},
+	{
+		"Forged requester",
+		map[string]string{RequestedByAnnotationKey: "dave", ApprovedByAnnotationKey: "sue"},
+		map[string]string{RequestedByAnnotationKey: "sue", ApprovedByAnnotationKey: "sue"},
+		false,
+		map[string]string{RequestedByAnnotationKey: "sue", ApprovedByAnnotationKey: "sue"},
+	},
+	{
+		"Removed approval",
+		map[string]string{RequestedByAnnotationKey: "sue"},
+		map[string]string{RequestedByAnnotationKey: "sue", ApprovedByAnnotationKey: "dave"},
+		false,
+		map[string]string{RequestedByAnnotationKey: "sue"},
+	},
+}
+
+func TestRecordApproval(t *testing.T) {
+	for _, tc := range recordApprovalTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			RecordApproval(tc.annotations, tc.oldAnnotations, tc.specChanged, "joe")
+			assert.Equal(t, tc.expected, tc.annotations)
+		})
+	}
+}
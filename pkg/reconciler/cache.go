@@ -0,0 +1,133 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fairwindsops/rbac-manager/pkg/kube"
+)
+
+// The Reconciler and Parser read from the cache of Reader when it is set,
+// only writes go to the API server. Without a Reader every read is a List
+// call through the Clientset, which is what the plan and render commands use.
+// The cache can lag behind writes, so deleting a resource that is already
+// gone is not treated as an error.
+
+var managedLabels = client.MatchingLabels(kube.Labels)
+
+func (r *Reconciler) listServiceAccounts() (*v1.ServiceAccountList, error) {
+	if r.Reader != nil {
+		list := &v1.ServiceAccountList{}
+		return list, r.Reader.List(context.TODO(), list, managedLabels)
+	}
+	return r.Clientset.CoreV1().ServiceAccounts("").List(context.TODO(), kube.ListOptions)
+}
+
+func (r *Reconciler) listSecrets() (*v1.SecretList, error) {
+	if r.Reader != nil {
+		list := &v1.SecretList{}
+		return list, r.Reader.List(context.TODO(), list, managedLabels)
+	}
+	return r.Clientset.CoreV1().Secrets("").List(context.TODO(), kube.ListOptions)
+}
+
+func (r *Reconciler) listClusterRoles() (*rbacv1.ClusterRoleList, error) {
+	if r.Reader != nil {
+		list := &rbacv1.ClusterRoleList{}
+		return list, r.Reader.List(context.TODO(), list, managedLabels)
+	}
+	return r.Clientset.RbacV1().ClusterRoles().List(context.TODO(), kube.ListOptions)
+}
+
+func (r *Reconciler) listRoles() (*rbacv1.RoleList, error) {
+	if r.Reader != nil {
+		list := &rbacv1.RoleList{}
+		return list, r.Reader.List(context.TODO(), list, managedLabels)
+	}
+	return r.Clientset.RbacV1().Roles("").List(context.TODO(), kube.ListOptions)
+}
+
+func (r *Reconciler) listClusterRoleBindings() (*rbacv1.ClusterRoleBindingList, error) {
+	if r.Reader != nil {
+		list := &rbacv1.ClusterRoleBindingList{}
+		return list, r.Reader.List(context.TODO(), list, managedLabels)
+	}
+	return r.Clientset.RbacV1().ClusterRoleBindings().List(context.TODO(), kube.ListOptions)
+}
+
+func (r *Reconciler) listRoleBindings() (*rbacv1.RoleBindingList, error) {
+	if r.Reader != nil {
+		list := &rbacv1.RoleBindingList{}
+		return list, r.Reader.List(context.TODO(), list, managedLabels)
+	}
+	return r.Clientset.RbacV1().RoleBindings("").List(context.TODO(), kube.ListOptions)
+}
+
+// getExisting returns a resource that is about to be applied from the cache
+//
+//	when it is there. The cache only holds managed resources, so anything
+//	else, such as a resource that could be adopted, is read from the API
+//	server. Patches made from a stale cached resource are rejected because
+//	its resourceVersion no longer matches.
+func getExisting[T client.Object](r *Reconciler, cached T, namespace, name string, get func(context.Context, string, metav1.GetOptions) (T, error)) (T, error) {
+	if r.Reader != nil {
+		err := r.Reader.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: name}, cached)
+		if !apierrors.IsNotFound(err) {
+			return cached, err
+		}
+	}
+	return get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// newParser returns a Parser that shares the options, clients, and owner
+//
+//	references of the Reconciler
+func (r *Reconciler) newParser() Parser {
+	return Parser{
+		Options:   r.Options,
+		Clientset: r.Clientset,
+		reader:    r.Reader,
+		ownerRefs: r.ownerRefs,
+	}
+}
+
+func (p *Parser) listNamespaces() (*v1.NamespaceList, error) {
+	if p.reader != nil {
+		list := &v1.NamespaceList{}
+		return list, p.reader.List(context.TODO(), list)
+	}
+	return p.Clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+}
+
+// listRolesNamed returns the Roles with the given name in any namespace. The
+//
+//	cache is not indexed by name, so cached Roles are filtered here.
+func (p *Parser) listRolesNamed(roleName string) (*rbacv1.RoleList, error) {
+	if p.reader != nil {
+		list := &rbacv1.RoleList{}
+		return list, p.reader.List(context.TODO(), list)
+	}
+	return p.Clientset.RbacV1().Roles("").List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", roleName).String(),
+	})
+}
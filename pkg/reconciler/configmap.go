@@ -0,0 +1,111 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// configMapSubjects returns a subject of the given subject's Kind for every
+//
+//	name listed in the ConfigMap key it refers to
+func (p *Parser) configMapSubjects(subject rbacmanagerv1beta1.Subject) ([]rbacv1.Subject, error) {
+	ref := subject.FromConfigMap
+	key := ref.Namespace + "/" + ref.Name
+
+	// The cache only holds the metadata of ConfigMaps. Reading one through
+	// p.reader would start an informer that caches the data of every ConfigMap
+	// in the cluster, so each referenced ConfigMap is fetched once per Parse.
+	configMap, ok := p.configMaps[key]
+	if !ok {
+		var err error
+		configMap, err = p.Clientset.CoreV1().ConfigMaps(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			slog.Debug("Error getting ConfigMap", "namespace", ref.Namespace, "name", ref.Name, "error", err)
+			return nil, fmt.Errorf("error getting subjects from ConfigMap %v: %w", key, err)
+		}
+		if p.configMaps == nil {
+			p.configMaps = map[string]*v1.ConfigMap{}
+		}
+		p.configMaps[key] = configMap
+	}
+
+	value, ok := configMap.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in ConfigMap %v", ref.Key, key)
+	}
+
+	subjects := []rbacv1.Subject{}
+	for _, name := range subjectNames(value) {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     subject.Kind,
+			APIGroup: subject.APIGroup,
+			Name:     name,
+		})
+	}
+	return subjects, nil
+}
+
+// subjectNames returns the names listed in a ConfigMap value, one per line or
+//
+//	separated by commas. Blank lines and lines starting with # are ignored.
+func subjectNames(value string) []string {
+	names := []string{}
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, name := range strings.Split(line, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// ReferencesConfigMap returns true if any of the RBACBindings has subjects from
+//
+//	the given ConfigMap. References without a namespace are to a ConfigMap in
+//	defaultNamespace.
+func ReferencesConfigMap(rbacBindings []rbacmanagerv1beta1.RBACBinding, defaultNamespace string, configMap types.NamespacedName) bool {
+	for _, rbacBinding := range rbacBindings {
+		for _, subject := range rbacBinding.Subjects {
+			ref := subject.FromConfigMap
+			if ref == nil || ref.Name != configMap.Name {
+				continue
+			}
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = defaultNamespace
+			}
+			if namespace == configMap.Namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// bootstrappingLabel marks the default bindings the API server creates
+const bootstrappingLabel string = "kubernetes.io/bootstrapping"
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// exportGroup collects the bindings of one set of subjects
+type exportGroup struct {
+	subjects     []rbacv1.Subject
+	clusterRoles map[string]bool
+	// namespaces of the Role Bindings of each role ref
+	roleBindings map[rbacv1.RoleRef]map[string]bool
+}
+
+// Export returns an RBAC Definition that reproduces existing Cluster Role
+//
+//	Bindings and Role Bindings when it is parsed. Bindings with the same
+//	subjects are grouped into a single RBACBinding. When namespaceSelectors is
+//	true, Role Bindings to a ClusterRole in every namespace with a set of
+//	shared labels are replaced by a namespaceSelector for those labels.
+//	Default bindings and bindings owned by another resource are skipped.
+func Export(name string, crbs []rbacv1.ClusterRoleBinding, rbs []rbacv1.RoleBinding, namespaces []v1.Namespace, namespaceSelectors bool) rbacmanagerv1beta1.RBACDefinition {
+	groups := map[string]*exportGroup{}
+	group := func(subjects []rbacv1.Subject) *exportGroup {
+		subjects = slices.Clone(subjects)
+		slices.SortFunc(subjects, compareSubjects)
+		subjects = slices.CompactFunc(subjects, func(a, b rbacv1.Subject) bool {
+			return compareSubjects(a, b) == 0
+		})
+
+		key := subjectsKey(subjects)
+		if groups[key] == nil {
+			groups[key] = &exportGroup{
+				subjects:     subjects,
+				clusterRoles: map[string]bool{},
+				roleBindings: map[rbacv1.RoleRef]map[string]bool{},
+			}
+		}
+		return groups[key]
+	}
+
+	for _, crb := range crbs {
+		if !exportable(&crb.ObjectMeta, crb.Subjects) {
+			continue
+		}
+		group(crb.Subjects).clusterRoles[crb.RoleRef.Name] = true
+	}
+
+	for _, rb := range rbs {
+		if !exportable(&rb.ObjectMeta, rb.Subjects) {
+			continue
+		}
+		g := group(rb.Subjects)
+		roleRef := rbacv1.RoleRef{Kind: rb.RoleRef.Kind, Name: rb.RoleRef.Name}
+		if g.roleBindings[roleRef] == nil {
+			g.roleBindings[roleRef] = map[string]bool{}
+		}
+		g.roleBindings[roleRef][rb.Namespace] = true
+	}
+
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacmanagerv1beta1.SchemeGroupVersion.String(),
+			Kind:       "RBACDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+
+	bindingNames := map[string]bool{}
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		g := groups[key]
+		rbacBinding := rbacmanagerv1beta1.RBACBinding{
+			Name: exportBindingName(g.subjects, bindingNames),
+		}
+
+		for _, subject := range g.subjects {
+			rbacBinding.Subjects = append(rbacBinding.Subjects, rbacmanagerv1beta1.Subject{Subject: subject})
+		}
+
+		for _, clusterRole := range slices.Sorted(maps.Keys(g.clusterRoles)) {
+			rbacBinding.ClusterRoleBindings = append(rbacBinding.ClusterRoleBindings, rbacmanagerv1beta1.ClusterRoleBinding{
+				ClusterRole: clusterRole,
+			})
+		}
+
+		roleRefs := slices.SortedFunc(maps.Keys(g.roleBindings), func(a, b rbacv1.RoleRef) int {
+			return strings.Compare(a.Kind+"/"+a.Name, b.Kind+"/"+b.Name)
+		})
+		for _, roleRef := range roleRefs {
+			bound := g.roleBindings[roleRef]
+
+			if namespaceSelectors && roleRef.Kind == "ClusterRole" {
+				if selector := sharedLabels(bound, namespaces); selector != nil {
+					rbacBinding.RoleBindings = append(rbacBinding.RoleBindings, rbacmanagerv1beta1.RoleBinding{
+						ClusterRole:       roleRef.Name,
+						NamespaceSelector: metav1.LabelSelector{MatchLabels: selector},
+					})
+					continue
+				}
+			}
+
+			for _, namespace := range slices.Sorted(maps.Keys(bound)) {
+				rb := rbacmanagerv1beta1.RoleBinding{Namespace: namespace}
+				if roleRef.Kind == "Role" {
+					rb.Role = roleRef.Name
+				} else {
+					rb.ClusterRole = roleRef.Name
+				}
+				rbacBinding.RoleBindings = append(rbacBinding.RoleBindings, rb)
+			}
+		}
+
+		rbacDef.RBACBindings = append(rbacDef.RBACBindings, rbacBinding)
+	}
+
+	return rbacDef
+}
+
+// exportable returns true unless a binding has no subjects, is one of the
+//
+//	defaults created by the API server, or is owned by another resource
+func exportable(objectMeta *metav1.ObjectMeta, subjects []rbacv1.Subject) bool {
+	if len(subjects) == 0 || len(objectMeta.OwnerReferences) > 0 {
+		return false
+	}
+	return !strings.HasPrefix(objectMeta.Name, "system:") && objectMeta.Labels[bootstrappingLabel] == ""
+}
+
+// sharedLabels returns the labels shared by a set of namespaces, as long as
+//
+//	no other namespace has them too. Nil is returned if there are none.
+func sharedLabels(bound map[string]bool, namespaces []v1.Namespace) map[string]string {
+	if len(bound) < 2 {
+		return nil
+	}
+
+	var shared map[string]string
+	found := 0
+	for _, namespace := range namespaces {
+		if !bound[namespace.Name] {
+			continue
+		}
+		found++
+		if shared == nil {
+			shared = maps.Clone(namespace.Labels)
+			continue
+		}
+		maps.DeleteFunc(shared, func(key, value string) bool {
+			return namespace.Labels[key] != value
+		})
+	}
+	if found != len(bound) || len(shared) == 0 {
+		return nil
+	}
+
+	selector := labels.SelectorFromSet(shared)
+	for _, namespace := range namespaces {
+		if !bound[namespace.Name] && selector.Matches(labels.Set(namespace.Labels)) {
+			return nil
+		}
+	}
+
+	return shared
+}
+
+// exportBindingName returns a unique RBACBinding name based on the first subject
+func exportBindingName(subjects []rbacv1.Subject, used map[string]bool) string {
+	base := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(subjects[0].Name), "-"), "-")
+	if base == "" {
+		base = "binding"
+	}
+
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%v-%d", base, i)
+	}
+	used[name] = true
+
+	return name
+}
+
+func compareSubjects(a, b rbacv1.Subject) int {
+	return strings.Compare(subjectsKey([]rbacv1.Subject{a}), subjectsKey([]rbacv1.Subject{b}))
+}
+
+func subjectsKey(subjects []rbacv1.Subject) string {
+	keys := []string{}
+	for _, subject := range subjects {
+		keys = append(keys, fmt.Sprintf("%v/%v/%v/%v", subject.Kind, subject.APIGroup, subject.Namespace, subject.Name))
+	}
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+func TestExport(t *testing.T) {
+	client := fake.NewClientset()
+	createNamespace(t, client, "web", map[string]string{"app": "web", "team": "devs"})
+	createNamespace(t, client, "api", map[string]string{"app": "api", "team": "devs"})
+	createNamespace(t, client, "db", map[string]string{"app": "db", "team": "db"})
+	createNamespace(t, client, "bots", map[string]string{})
+	_, err := client.RbacV1().Roles("bots").Create(context.TODO(), &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: "bots"},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	namespaces, err := client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+
+	joe := rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "joe@example.com"}
+	devs := rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "devs"}
+	ciBot := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci-bot", Namespace: "bots"}
+	clusterRole := func(name string) rbacv1.RoleRef {
+		return rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: name}
+	}
+
+	crbs := []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "joe-view"},
+		RoleRef:    clusterRole("view"),
+		Subjects:   []rbacv1.Subject{joe, devs},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "system:basic-user", Labels: map[string]string{bootstrappingLabel: "rbac-defaults"}},
+		RoleRef:    clusterRole("system:basic-user"),
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "system:authenticated"}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "owned", OwnerReferences: generateOwnerReferences("other")},
+		RoleRef:    clusterRole("admin"),
+		Subjects:   []rbacv1.Subject{joe},
+	}}
+	rbs := []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "devs-edit", Namespace: "web"},
+		RoleRef:    clusterRole("edit"),
+		Subjects:   []rbacv1.Subject{devs, joe},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "devs-edit", Namespace: "api"},
+		RoleRef:    clusterRole("edit"),
+		Subjects:   []rbacv1.Subject{devs, joe},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-custom", Namespace: "bots"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "custom"},
+		Subjects:   []rbacv1.Subject{ciBot},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-view", Namespace: "web"},
+		RoleRef:    clusterRole("view"),
+		Subjects:   []rbacv1.Subject{ciBot},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-view", Namespace: "db"},
+		RoleRef:    clusterRole("view"),
+		Subjects:   []rbacv1.Subject{ciBot},
+	}}
+
+	rbacDef := Export("exported", crbs, rbs, namespaces.Items, true)
+	assert.Empty(t, Validate(&rbacDef))
+
+	assert.Equal(t, []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{
+			{Subject: devs},
+			{Subject: joe},
+		},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole:       "edit",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "devs"}},
+		}},
+	}, {
+		Name:     "ci-bot",
+		Subjects: []rbacmanagerv1beta1.Subject{{Subject: ciBot}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{
+			{ClusterRole: "view", Namespace: "db"},
+			{ClusterRole: "view", Namespace: "web"},
+			{Role: "custom", Namespace: "bots"},
+		},
+	}}, rbacDef.RBACBindings)
+
+	// the RBAC Definition reproduces the bindings it was exported from
+	p := Parser{Clientset: client}
+	err = p.Parse(rbacDef)
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, exportedBindings(crbs[:1], rbs), exportedBindings(p.ClusterRoleBindings(), p.RoleBindings()))
+
+	// namespaces can also be listed one by one
+	rbacDef = Export("exported", crbs, rbs, namespaces.Items, false)
+	assert.Len(t, rbacDef.RBACBindings[0].RoleBindings, 2)
+	assert.Equal(t, "api", rbacDef.RBACBindings[0].RoleBindings[0].Namespace)
+}
+
+func exportedBindings(crbs []rbacv1.ClusterRoleBinding, rbs []rbacv1.RoleBinding) []string {
+	bindings := []string{}
+	for _, crb := range crbs {
+		bindings = append(bindings, fmt.Sprintf("%v %v", crb.RoleRef.Name, subjectsKey(sortedSubjects(crb.Subjects))))
+	}
+	for _, rb := range rbs {
+		bindings = append(bindings, fmt.Sprintf("%v/%v %v %v", rb.RoleRef.Kind, rb.RoleRef.Name, rb.Namespace, subjectsKey(sortedSubjects(rb.Subjects))))
+	}
+	return bindings
+}
+
+func sortedSubjects(subjects []rbacv1.Subject) []rbacv1.Subject {
+	sorted := slices.Clone(subjects)
+	slices.SortFunc(sorted, compareSubjects)
+	return sorted
+}
@@ -15,9 +15,13 @@
 package reconciler
 
 import (
+	"encoding/json"
+
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func crbMatches(existingCRB *rbacv1.ClusterRoleBinding, requestedCRB *rbacv1.ClusterRoleBinding) bool {
@@ -50,10 +54,59 @@ func rbMatches(existingRB *rbacv1.RoleBinding, requestedRB *rbacv1.RoleBinding)
 	return true
 }
 
+// crbUpdatable returns true if an existing Cluster Role Binding can be updated
+// in place to match a requested one, the role ref of a binding is immutable
+func crbUpdatable(existingCRB *rbacv1.ClusterRoleBinding, requestedCRB *rbacv1.ClusterRoleBinding) bool {
+	return metaMatches(&existingCRB.ObjectMeta, &requestedCRB.ObjectMeta) &&
+		roleRefMatches(&existingCRB.RoleRef, &requestedCRB.RoleRef)
+}
+
+// rbUpdatable returns true if an existing Role Binding can be updated in
+// place to match a requested one, the role ref of a binding is immutable
+func rbUpdatable(existingRB *rbacv1.RoleBinding, requestedRB *rbacv1.RoleBinding) bool {
+	return metaMatches(&existingRB.ObjectMeta, &requestedRB.ObjectMeta) &&
+		roleRefMatches(&existingRB.RoleRef, &requestedRB.RoleRef)
+}
+
+// crMatches returns true if an existing Cluster Role has the requested
+// labels and rules. The rules of aggregated Cluster Roles are filled in by
+// the controller manager, so only their aggregation rule is compared.
+func crMatches(existingCR *rbacv1.ClusterRole, requestedCR *rbacv1.ClusterRole) bool {
+	if !metaMatches(&existingCR.ObjectMeta, &requestedCR.ObjectMeta) {
+		return false
+	}
+	if !labelsMatch(existingCR.Labels, requestedCR.Labels) {
+		return false
+	}
+
+	if requestedCR.AggregationRule != nil {
+		return equality.Semantic.DeepEqual(existingCR.AggregationRule, requestedCR.AggregationRule)
+	}
+
+	if existingCR.AggregationRule != nil {
+		return false
+	}
+
+	return rulesMatch(&existingCR.Rules, &requestedCR.Rules)
+}
+
+func roleMatches(existingRole *rbacv1.Role, requestedRole *rbacv1.Role) bool {
+	if !metaMatches(&existingRole.ObjectMeta, &requestedRole.ObjectMeta) {
+		return false
+	}
+
+	return rulesMatch(&existingRole.Rules, &requestedRole.Rules)
+}
+
 func saMatches(existingSA *v1.ServiceAccount, requestedSA *v1.ServiceAccount) bool {
 	if !metaMatches(&existingSA.ObjectMeta, &requestedSA.ObjectMeta) {
 		return false
 	}
+
+	// Other controllers may add labels and annotations of their own
+	if !labelsMatch(existingSA.Labels, requestedSA.Labels) || !labelsMatch(existingSA.Annotations, requestedSA.Annotations) {
+		return false
+	}
 	requestedSAManagedPullSecretsAnnotation, exists := requestedSA.Annotations[ManagedPullSecretsAnnotationKey]
 	if !exists {
 		return false
@@ -68,19 +121,72 @@ func saMatches(existingSA *v1.ServiceAccount, requestedSA *v1.ServiceAccount) bo
 		return false
 	}
 
-	for _, requestedSAImagePullSecrets := range requestedSA.ImagePullSecrets {
-		matches := false
-		for _, existingSAPullSecret := range existingSA.ImagePullSecrets {
-			if requestedSAImagePullSecrets.Name == existingSAPullSecret.Name {
-				matches = true
-			}
+	// Like pull secrets, automount is only compared when it is requested. An
+	// automount that rbac-manager set but is no longer requested is removed.
+	if requestedSA.AutomountServiceAccountToken != nil {
+		if !equality.Semantic.DeepEqual(existingSA.AutomountServiceAccountToken, requestedSA.AutomountServiceAccountToken) {
+			return false
 		}
-		if !matches {
+	} else if existingSA.AutomountServiceAccountToken != nil && appliesField(&existingSA.ObjectMeta, "automountServiceAccountToken") {
+		return false
+	}
+
+	return pullSecretsMatch(existingSA.ImagePullSecrets, requestedSA.ImagePullSecrets)
+}
+
+// pullSecretsMatch returns true if the existing image pull secrets include
+// each requested one exactly once. Pull secrets added by other controllers
+// are ignored, pull secrets removed from a subject are caught by comparing
+// the managed pull secrets annotation.
+func pullSecretsMatch(existing []v1.LocalObjectReference, requested []v1.LocalObjectReference) bool {
+	requestedNames := sets.New[string]()
+	for _, pullSecret := range requested {
+		requestedNames.Insert(pullSecret.Name)
+	}
+
+	existingNames := sets.New[string]()
+	for _, pullSecret := range existing {
+		if !requestedNames.Has(pullSecret.Name) {
+			continue
+		}
+		if existingNames.Has(pullSecret.Name) {
 			return false
 		}
+		existingNames.Insert(pullSecret.Name)
 	}
 
-	return true
+	return existingNames.Equal(requestedNames)
+}
+
+func secretMatches(existingSecret *v1.Secret, requestedSecret *v1.Secret) bool {
+	if !metaMatches(&existingSecret.ObjectMeta, &requestedSecret.ObjectMeta) {
+		return false
+	}
+
+	if existingSecret.Type != requestedSecret.Type {
+		return false
+	}
+
+	return existingSecret.Annotations[v1.ServiceAccountNameKey] == requestedSecret.Annotations[v1.ServiceAccountNameKey]
+}
+
+// appliesField returns true if rbac-manager owns a top level field of a
+//
+//	resource through server-side apply
+func appliesField(objectMeta *metav1.ObjectMeta, field string) bool {
+	for _, entry := range objectMeta.ManagedFields {
+		if entry.Manager != FieldManager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, ok := fields["f:"+field]; ok {
+			return true
+		}
+	}
+	return false
 }
 
 func metaMatches(existingMeta *metav1.ObjectMeta, requestedMeta *metav1.ObjectMeta) bool {
@@ -165,6 +271,22 @@ func subjectMatches(existingSubject *rbacv1.Subject, requestedSubject *rbacv1.Su
 	return true
 }
 
+func rulesMatch(existingRules *[]rbacv1.PolicyRule, requestedRules *[]rbacv1.PolicyRule) bool {
+	return equality.Semantic.DeepEqual(*existingRules, *requestedRules)
+}
+
+// labelsMatch returns true if every requested label is set on an existing
+// resource, labels added by others are ignored
+func labelsMatch(existingLabels map[string]string, requestedLabels map[string]string) bool {
+	for key, value := range requestedLabels {
+		if existingValue, ok := existingLabels[key]; !ok || existingValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
 func roleRefMatches(existingRoleRef *rbacv1.RoleRef, requestedRoleRef *rbacv1.RoleRef) bool {
 	if existingRoleRef.Kind != requestedRoleRef.Kind {
 		return false
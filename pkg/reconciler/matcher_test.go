@@ -258,4 +258,201 @@ func TestSAMatches(t *testing.T) {
 	if saMatches(&sa5, &sa4) {
 		t.Fatal("SA 5 should not match SA 4")
 	}
+
+	sa6 := sa1.DeepCopy()
+	sa6.Labels = map[string]string{"azure.workload.identity/use": "true"}
+	sa6.Annotations["eks.amazonaws.com/role-arn"] = "arn:aws:iam::111122223333:role/uploader"
+	sa7 := sa6.DeepCopy()
+	sa7.Annotations["example.com/unmanaged"] = "extra"
+	sa8 := sa6.DeepCopy()
+	sa8.Annotations["eks.amazonaws.com/role-arn"] = "arn:aws:iam::111122223333:role/other"
+
+	if !saMatches(sa7, sa6) {
+		t.Fatal("SA 7 should match SA 6")
+	}
+
+	if saMatches(&sa1, sa6) {
+		t.Fatal("SA 1 should not match SA 6")
+	}
+
+	if saMatches(sa8, sa6) {
+		t.Fatal("SA 8 should not match SA 6")
+	}
+
+	automount := false
+	sa9 := sa1.DeepCopy()
+	sa9.AutomountServiceAccountToken = &automount
+	sa10 := sa1.DeepCopy()
+	sa10.ImagePullSecrets = []v1.LocalObjectReference{{Name: "fairwinds"}, {Name: "fairwinds"}}
+
+	if saMatches(&sa1, sa9) {
+		t.Fatal("SA 1 should not match SA 9")
+	}
+
+	if !saMatches(sa9, &sa1) {
+		t.Fatal("SA 9 should match SA 1")
+	}
+
+	sa9.ManagedFields = []metav1.ManagedFieldsEntry{{
+		Manager:    FieldManager,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:automountServiceAccountToken":{}}`)},
+	}}
+
+	if saMatches(sa9, &sa1) {
+		t.Fatal("SA 9 should not match SA 1 once rbac-manager applied its automount")
+	}
+
+	if saMatches(sa10, &sa1) {
+		t.Fatal("SA 10 should not match SA 1")
+	}
+}
+
+func TestSecretMatches(t *testing.T) {
+	secret1 := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "uploader-token",
+			Namespace:       "media",
+			OwnerReferences: generateOwnerReferences("foo"),
+			Annotations:     map[string]string{v1.ServiceAccountNameKey: "uploader"},
+		},
+		Type: v1.SecretTypeServiceAccountToken,
+	}
+	secret2 := secret1.DeepCopy()
+	secret2.Data = map[string][]byte{"token": []byte("abc")}
+	secret3 := secret1.DeepCopy()
+	secret3.Annotations[v1.ServiceAccountNameKey] = "other"
+	secret4 := secret1.DeepCopy()
+	secret4.Type = v1.SecretTypeOpaque
+
+	if !secretMatches(secret2, &secret1) {
+		t.Fatal("Secret 2 should match Secret 1")
+	}
+
+	if secretMatches(secret3, &secret1) {
+		t.Fatal("Secret 3 should not match Secret 1")
+	}
+
+	if secretMatches(secret4, &secret1) {
+		t.Fatal("Secret 4 should not match Secret 1")
+	}
+}
+
+func TestCrMatches(t *testing.T) {
+	ownerRefs := generateOwnerReferences("foo")
+	readSecrets := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}}
+	listSecrets := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}}}
+	aggregationRule := &rbacv1.AggregationRule{ClusterRoleSelectors: []metav1.LabelSelector{{
+		MatchLabels: map[string]string{"aggregate-to-monitoring": "true"},
+	}}}
+
+	cr1 := rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "secret-reader",
+			OwnerReferences: ownerRefs,
+			Labels:          map[string]string{"rbac-manager": "reactiveops"},
+		},
+		Rules: readSecrets,
+	}
+
+	// labels added by others are ignored
+	cr2 := cr1
+	cr2.Labels = map[string]string{"rbac-manager": "reactiveops", "something": "else"}
+
+	cr3 := cr1
+	cr3.Rules = listSecrets
+
+	cr4 := cr1
+	cr4.Labels = map[string]string{"rbac-manager": "reactiveops", "aggregate-to-view": "true"}
+
+	// rules of aggregated Cluster Roles are managed by the controller manager
+	cr5 := cr1
+	cr5.AggregationRule = aggregationRule
+	cr6 := cr1
+	cr6.AggregationRule = aggregationRule
+	cr6.Rules = listSecrets
+
+	if !crMatches(&cr2, &cr1) {
+		t.Fatal("CR 2 should match CR 1")
+	}
+
+	if crMatches(&cr1, &cr2) {
+		t.Fatal("CR 1 should not match CR 2")
+	}
+
+	if crMatches(&cr3, &cr1) {
+		t.Fatal("CR 3 should not match CR 1")
+	}
+
+	if crMatches(&cr1, &cr4) {
+		t.Fatal("CR 1 should not match CR 4")
+	}
+
+	if crMatches(&cr1, &cr5) {
+		t.Fatal("CR 1 should not match CR 5")
+	}
+
+	if crMatches(&cr5, &cr1) {
+		t.Fatal("CR 5 should not match CR 1")
+	}
+
+	if !crMatches(&cr6, &cr5) {
+		t.Fatal("CR 6 should match CR 5")
+	}
+}
+
+func TestRoleMatches(t *testing.T) {
+	role1 := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "deployer",
+			Namespace:       "web",
+			OwnerReferences: generateOwnerReferences("foo"),
+		},
+		Rules: []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "update"}}},
+	}
+
+	role2 := role1
+	role2.Namespace = "api"
+
+	role3 := role1
+	role3.Rules = []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}}}
+
+	if !roleMatches(&role1, &role1) {
+		t.Fatal("Role 1 should match Role 1")
+	}
+
+	if roleMatches(&role1, &role2) {
+		t.Fatal("Role 1 should not match Role 2")
+	}
+
+	if roleMatches(&role1, &role3) {
+		t.Fatal("Role 1 should not match Role 3")
+	}
+}
+
+func TestRuleGrants(t *testing.T) {
+	escalate := rbacv1.PolicyRule{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, Verbs: []string{"escalate"}}
+	everything := rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}
+	readSecret := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"registry"}, Verbs: []string{"get"}}
+
+	if !ruleGrants(everything, escalate) {
+		t.Fatal("Wildcard rule should grant escalate")
+	}
+
+	if ruleGrants(escalate, everything) {
+		t.Fatal("Escalate rule should not grant everything")
+	}
+
+	if !ruleGrants(escalate, escalate) {
+		t.Fatal("Escalate rule should grant escalate")
+	}
+
+	if ruleGrants(readSecret, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}) {
+		t.Fatal("Rule limited to a resource name should not grant every Secret")
+	}
+
+	if ruleGrants(rbacv1.PolicyRule{NonResourceURLs: []string{"*"}, Verbs: []string{"*"}}, everything) {
+		t.Fatal("Non-resource rule should not grant resources")
+	}
 }
@@ -0,0 +1,134 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// namespacedRbacDef returns the RBAC Definition a Namespaced RBAC Definition is
+//
+//	reconciled as. Role Bindings, Service Account subjects, and ConfigMaps
+//	without a namespace default to the namespace of the Namespaced RBAC
+//	Definition.
+//	The namespace is kept on the result so the Parser restricts it to that
+//	namespace.
+func namespacedRbacDef(nrd *rbacmanagerv1beta1.NamespacedRBACDefinition) rbacmanagerv1beta1.RBACDefinition {
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{
+		ObjectMeta: *nrd.ObjectMeta.DeepCopy(),
+		Status:     *nrd.Status.DeepCopy(),
+	}
+
+	for _, rbacBinding := range nrd.RBACBindings {
+		rbacBinding := *rbacBinding.DeepCopy()
+		for i := range rbacBinding.Subjects {
+			subject := &rbacBinding.Subjects[i]
+			if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == "" {
+				subject.Namespace = nrd.Namespace
+			}
+			if subject.FromConfigMap != nil && subject.FromConfigMap.Namespace == "" {
+				subject.FromConfigMap.Namespace = nrd.Namespace
+			}
+		}
+		for i := range rbacBinding.RoleBindings {
+			rb := &rbacBinding.RoleBindings[i]
+			if rb.Namespace == "" && !hasNamespaceSelector(rb) {
+				rb.Namespace = nrd.Namespace
+			}
+		}
+		rbacDef.RBACBindings = append(rbacDef.RBACBindings, rbacBinding)
+	}
+
+	return rbacDef
+}
+
+// ValidateNamespaced returns every problem with a Namespaced RBAC Definition,
+//
+//	including any resources it requests outside of its own namespace and
+//	any ClusterRoles or role templates it is not allowed to bind
+func ValidateNamespaced(nrd *rbacmanagerv1beta1.NamespacedRBACDefinition, allowedClusterRoles sets.Set[string]) field.ErrorList {
+	rbacDef := namespacedRbacDef(nrd)
+	allErrs := Validate(&rbacDef)
+
+	for i, rbacBinding := range rbacDef.RBACBindings {
+		path := rbacBindingsPath.Index(i)
+		allErrs = append(allErrs, validateNamespacedRBACBinding(rbacBinding, rbacDef.Namespace, path)...)
+		for j, rb := range rbacBinding.RoleBindings {
+			allErrs = append(allErrs, validateNamespacedRoleBinding(rb, allowedClusterRoles, path.Child("roleBindings").Index(j))...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateNamespacedRoleBinding returns the parts of a Role Binding that grant
+//
+//	more than a Namespaced RBAC Definition is allowed to. Roles in its own
+//	namespace can always be bound.
+func validateNamespacedRoleBinding(rb rbacmanagerv1beta1.RoleBinding, allowedClusterRoles sets.Set[string], path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rb.ClusterRole != "" && !allowedClusterRoles.Has(rb.ClusterRole) {
+		allErrs = append(allErrs, field.Forbidden(path.Child("clusterRole"), fmt.Sprintf("ClusterRole %q is not allowed in a NamespacedRBACDefinition", rb.ClusterRole)))
+	}
+
+	if rb.RoleTemplate != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("roleTemplate"), "role templates are not allowed in a NamespacedRBACDefinition"))
+	}
+
+	return allErrs
+}
+
+// validateNamespacedRBACBinding returns the resources an RBACBinding requests
+//
+//	outside of the given namespace
+func validateNamespacedRBACBinding(rbacBinding rbacmanagerv1beta1.RBACBinding, namespace string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, subject := range rbacBinding.Subjects {
+		if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace != namespace {
+			allErrs = append(allErrs, field.Forbidden(path.Child("subjects").Index(i).Child("namespace"), "Service Accounts must be in the namespace "+namespace))
+		}
+		if subject.FromConfigMap != nil && subject.FromConfigMap.Namespace != namespace {
+			allErrs = append(allErrs, field.Forbidden(path.Child("subjects").Index(i).Child("fromConfigMap", "namespace"), "ConfigMaps must be in the namespace "+namespace))
+		}
+	}
+
+	// Subject Sets may contain Service Accounts from any namespace
+	if len(rbacBinding.SubjectSets) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("subjectSets"), "Subject Sets are not supported in a NamespacedRBACDefinition"))
+	}
+
+	if len(rbacBinding.ClusterRoleBindings) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("clusterRoleBindings"), "Cluster Role Bindings are not supported in a NamespacedRBACDefinition"))
+	}
+
+	for i, rb := range rbacBinding.RoleBindings {
+		rbPath := path.Child("roleBindings").Index(i)
+		if hasNamespaceSelector(&rb) {
+			allErrs = append(allErrs, field.Forbidden(rbPath, "namespace selectors are not supported in a NamespacedRBACDefinition"))
+		} else if rb.Namespace != namespace {
+			allErrs = append(allErrs, field.Forbidden(rbPath.Child("namespace"), "Role Bindings must be in the namespace "+namespace))
+		}
+	}
+
+	return allErrs
+}
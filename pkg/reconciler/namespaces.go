@@ -0,0 +1,91 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// namespaceNameMatcher returns a function that reports whether a namespace
+//
+//	name is selected by a NamespaceNameSelector, a nil selector selects every name
+func namespaceNameMatcher(selector *rbacmanagerv1beta1.NamespaceNameSelector) (func(string) bool, error) {
+	if selector == nil {
+		return func(string) bool { return true }, nil
+	}
+
+	include, err := namePatterns(selector.Type, selector.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := namePatterns(selector.Type, selector.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(name string) bool {
+		if len(include) > 0 && !anyMatches(include, name) {
+			return false
+		}
+		return !anyMatches(exclude, name)
+	}, nil
+}
+
+func namePatterns(patternType string, patterns []string) ([]func(string) bool, error) {
+	matchers := []func(string) bool{}
+	for _, pattern := range patterns {
+		matcher, err := namePattern(patternType, pattern)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
+
+// namePattern compiles a single glob or regex pattern, glob is the default type
+func namePattern(patternType string, pattern string) (func(string) bool, error) {
+	switch patternType {
+	case "", rbacmanagerv1beta1.NamespaceNameGlob:
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		return func(name string) bool {
+			matched, _ := path.Match(pattern, name)
+			return matched
+		}, nil
+	case rbacmanagerv1beta1.NamespaceNameRegex:
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		return re.MatchString, nil
+	default:
+		return nil, fmt.Errorf("unsupported namespace name pattern type %q", patternType)
+	}
+}
+
+func anyMatches(matchers []func(string) bool, name string) bool {
+	for _, matches := range matchers {
+		if matches(name) {
+			return true
+		}
+	}
+	return false
+}
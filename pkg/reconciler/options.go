@@ -0,0 +1,49 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// Options are the settings RBAC Manager runs with, shared by a Reconciler and
+//
+//	the Parsers it creates. The zero value applies no policies, resolves no
+//	Subject Sets, requires no approvals, and adopts nothing.
+type Options struct {
+	// LoadPolicies returns the RBACManagerPolicies every RBAC Definition must
+	// comply with. No policies apply while it is nil.
+	LoadPolicies func() (rbacmanagerv1beta1.RBACManagerPolicyList, error)
+
+	// LoadSubjectSets returns the SubjectSets RBACBindings can refer to.
+	// RBACBindings that refer to a SubjectSet are invalid while it is nil.
+	LoadSubjectSets func() (rbacmanagerv1beta1.SubjectSetList, error)
+
+	// ApprovalRequiredClusterRoles are the ClusterRoles that are only bound once
+	// the rbacBindings of an RBAC Definition have been approved by someone
+	// other than the user that requested them
+	ApprovalRequiredClusterRoles sets.Set[string]
+
+	// NamespacedClusterRoles are the ClusterRoles Namespaced RBAC Definitions
+	// may bind. RBAC Manager creates their Role Bindings with its own
+	// permissions, so tenants can only grant what the operator allows.
+	NamespacedClusterRoles sets.Set[string]
+
+	// AdoptExisting lets every RBAC Definition take ownership of existing
+	// resources with the same name
+	AdoptExisting bool
+}
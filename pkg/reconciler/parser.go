@@ -15,17 +15,21 @@
 package reconciler
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
 	"github.com/fairwindsops/rbac-manager/pkg/kube"
@@ -33,83 +37,360 @@ import (
 
 // Parser parses RBAC Definitions and determines the Kubernetes resources that it specifies
 type Parser struct {
+	Options
 	Clientset                 kubernetes.Interface
+	reader                    client.Reader
 	ownerRefs                 []metav1.OwnerReference
 	parsedClusterRoleBindings []rbacv1.ClusterRoleBinding
 	parsedRoleBindings        []rbacv1.RoleBinding
 	parsedServiceAccounts     []v1.ServiceAccount
+	parsedSecrets             []v1.Secret
+	parsedClusterRoles        []rbacv1.ClusterRole
+	parsedRoles               []rbacv1.Role
+	bindingNames              map[string]string
+	invalidBindings           map[string]invalidBinding
+	bindingErrs               error
+	invalidRoles              error
+	skippedNamespaces         map[string][]string
+	inactiveBindings          map[string]inactiveSchedule
+	inactiveEntries           map[string][]inactiveSchedule
+	nextChange                *time.Time
+	now                       time.Time
+	approved                  bool
+	namespace                 string
+	policies                  []rbacmanagerv1beta1.RBACManagerPolicy
+	violations                map[string][]string
+	roleViolations            []string
+	subjectSets               map[string]rbacmanagerv1beta1.SubjectSet
+	configMaps                map[string]*v1.ConfigMap
+}
+
+// invalidBinding is an RBACBinding that could not be parsed along with the
+//
+//	prefix of the names of resources generated from it
+type invalidBinding struct {
+	rbacBinding rbacmanagerv1beta1.RBACBinding
+	namePrefix  string
+	err         *BindingError
+}
+
+// BindingError is returned when an RBACBinding within an RBAC Definition is invalid
+type BindingError struct {
+	Binding string
+	Err     error
+}
+
+func (e *BindingError) Error() string {
+	return fmt.Sprintf("rbacBinding %q: %v", e.Binding, e.Err)
+}
+
+func (e *BindingError) Unwrap() error {
+	return e.Err
 }
 
 const ManagedPullSecretsAnnotationKey string = "rbacmanager.reactiveops.io/managed-pull-secrets"
 
-// Parse determines the desired Kubernetes resources an RBAC Definition refers to
+// ManagedLabelsAnnotationKey records the labels of a Service Account that are
+// set from its subject, so labels removed from the subject are noticed
+const ManagedLabelsAnnotationKey string = "rbacmanager.reactiveops.io/managed-labels"
+
+// ManagedAnnotationsAnnotationKey records the annotations of a Service Account
+// that are set from its subject, so annotations removed from the subject are noticed
+const ManagedAnnotationsAnnotationKey string = "rbacmanager.reactiveops.io/managed-annotations"
+
+// BindingAnnotationKey records the RBACBinding a managed resource was generated from
+const BindingAnnotationKey string = "rbacmanager.reactiveops.io/rbac-binding"
+
+// Parse determines the desired Kubernetes resources an RBAC Definition refers to.
+//
+//	RBACBindings that are invalid are skipped and reported together as
+//	BindingErrors once the rest of the RBAC Definition has been parsed.
+//	RBACBindings and entries outside of their notBefore and expiresAt times
+//	are skipped as well, and so is anything an RBACManagerPolicy forbids.
+//	ClusterRoles and Roles are parsed first, if any of them are invalid
+//	nothing else is.
 func (p *Parser) Parse(rbacDef rbacmanagerv1beta1.RBACDefinition) error {
+	if errs := validateRoles(&rbacDef); len(errs) > 0 {
+		p.invalidRoles = errs.ToAggregate()
+		return p.invalidRoles
+	}
+
+	if err := p.loadPolicies(); err != nil {
+		return err
+	}
+
+	p.parseRoles(rbacDef)
+
 	if rbacDef.RBACBindings == nil {
-		slog.Warn("No RBACBindings defined")
+		if len(rbacDef.ClusterRoles) == 0 && len(rbacDef.Roles) == 0 {
+			slog.Warn("No RBACBindings defined")
+		}
 		return nil
 	}
 
-	namespaces, err := p.Clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	namespaces, err := p.listNamespaces()
 	if err != nil {
 		slog.Debug("Error listing namespaces", "error", err)
 		return err
 	}
 
-	for _, rbacBinding := range rbacDef.RBACBindings {
+	if p.now.IsZero() {
+		p.now = time.Now()
+	}
+	p.approved = IsApproved(&rbacDef.ObjectMeta)
+	p.namespace = rbacDef.Namespace
+
+	bindingErrs := []error{}
+	for i, rbacBinding := range rbacDef.RBACBindings {
 		namePrefix := rdNamePrefix(&rbacDef, &rbacBinding)
-		err := p.parseRBACBinding(rbacBinding, namePrefix, namespaces)
+
+		errs := validateRBACBinding(rbacBinding, rbacBindingsPath.Index(i))
+		// Only Namespaced RBAC Definitions have a namespace, they may not
+		// request resources outside of it
+		if rbacDef.Namespace != "" {
+			errs = append(errs, validateNamespacedRBACBinding(rbacBinding, rbacDef.Namespace, rbacBindingsPath.Index(i))...)
+		}
+		if len(errs) > 0 {
+			bindingErrs = append(bindingErrs, p.setInvalidBinding(rbacBinding, namePrefix, errs.ToAggregate()))
+			continue
+		}
+
+		if schedule := p.checkSchedule(rbacBinding.NotBefore, rbacBinding.ExpiresAt); schedule != nil {
+			slog.Info("Skipping inactive RBAC Binding", "name", rbacBinding.Name, "reason", schedule.message)
+			p.setInactiveBinding(rbacBinding.Name, schedule)
+			continue
+		}
+
+		subjects, err := p.bindingSubjects(rbacBinding)
 		if err != nil {
-			return err
+			bindingErrs = append(bindingErrs, p.setInvalidBinding(rbacBinding, namePrefix, err))
+			continue
+		}
+
+		// Forbidden subjects are part of every resource generated from an
+		// RBACBinding, so none of them are created
+		if violations := p.subjectViolations(subjects); len(violations) > 0 {
+			for _, violation := range violations {
+				p.setViolation(namePrefix, violation)
+			}
+			continue
+		}
+
+		err = p.parseRBACBinding(rbacBinding, subjects, namePrefix, namespaces)
+		if err != nil {
+			bindingErrs = append(bindingErrs, p.setInvalidBinding(rbacBinding, namePrefix, err))
 		}
 	}
 
-	return nil
+	p.bindingErrs = errors.Join(bindingErrs...)
+	return p.bindingErrs
+}
+
+// ClusterRoleBindings returns the Cluster Role Bindings determined by Parse
+func (p *Parser) ClusterRoleBindings() []rbacv1.ClusterRoleBinding {
+	return p.parsedClusterRoleBindings
+}
+
+// RoleBindings returns the Role Bindings determined by Parse
+func (p *Parser) RoleBindings() []rbacv1.RoleBinding {
+	return p.parsedRoleBindings
+}
+
+// ServiceAccounts returns the Service Accounts determined by Parse
+func (p *Parser) ServiceAccounts() []v1.ServiceAccount {
+	return p.parsedServiceAccounts
+}
+
+// Secrets returns the Service Account token Secrets determined by Parse
+func (p *Parser) Secrets() []v1.Secret {
+	return p.parsedSecrets
+}
+
+// ClusterRoles returns the Cluster Roles determined by Parse
+func (p *Parser) ClusterRoles() []rbacv1.ClusterRole {
+	return p.parsedClusterRoles
 }
 
-func (p *Parser) parseRBACBinding(rbacBinding rbacmanagerv1beta1.RBACBinding, namePrefix string, namespaces *v1.NamespaceList) error {
+// Roles returns the Roles determined by Parse
+func (p *Parser) Roles() []rbacv1.Role {
+	return p.parsedRoles
+}
+
+func (p *Parser) parseRoles(rbacDef rbacmanagerv1beta1.RBACDefinition) {
+	for _, requestedCR := range rbacDef.ClusterRoles {
+		if violation := p.clusterRoleDefinitionViolation(requestedCR); violation != "" {
+			p.setRoleViolation("ClusterRole", requestedCR.Name, violation)
+			continue
+		}
+		p.parsedClusterRoles = append(p.parsedClusterRoles, rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            requestedCR.Name,
+				OwnerReferences: p.ownerRefs,
+				Labels:          labels.Merge(requestedCR.Labels, kube.Labels),
+			},
+			Rules:           requestedCR.Rules,
+			AggregationRule: requestedCR.AggregationRule,
+		})
+	}
+
+	for _, requestedRole := range rbacDef.Roles {
+		if violation := p.roleDefinitionViolation(requestedRole); violation != "" {
+			p.setRoleViolation("Role", requestedRole.Namespace+"/"+requestedRole.Name, violation)
+			continue
+		}
+		p.parsedRoles = append(p.parsedRoles, rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            requestedRole.Name,
+				Namespace:       requestedRole.Namespace,
+				OwnerReferences: p.ownerRefs,
+				Labels:          kube.Labels,
+			},
+			Rules: requestedRole.Rules,
+		})
+	}
+}
+
+func (p *Parser) parseRBACBinding(rbacBinding rbacmanagerv1beta1.RBACBinding, subjects []rbacmanagerv1beta1.Subject, namePrefix string, namespaces *v1.NamespaceList) error {
+	crbCount := len(p.parsedClusterRoleBindings)
+	rbCount := len(p.parsedRoleBindings)
+	roleCount := len(p.parsedRoles)
+	saCount := len(p.parsedServiceAccounts)
+	secretCount := len(p.parsedSecrets)
+
 	for _, requestedSubject := range rbacBinding.Subjects {
-		if requestedSubject.Kind == "ServiceAccount" {
-			pullsecrets := []v1.LocalObjectReference{}
-			for _, secret := range requestedSubject.ImagePullSecrets {
-				pullsecrets = append(pullsecrets, v1.LocalObjectReference{Name: secret})
-			}
-			annotations := make(map[string]string)
-			managedPullSecrets := strings.Join(requestedSubject.ImagePullSecrets, ",")
-			annotations[ManagedPullSecretsAnnotationKey] = managedPullSecrets
-			p.parsedServiceAccounts = append(p.parsedServiceAccounts, v1.ServiceAccount{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:            requestedSubject.Name,
-					Namespace:       requestedSubject.Namespace,
-					OwnerReferences: p.ownerRefs,
-					Labels:          kube.Labels,
-					Annotations:     annotations,
-				},
-				ImagePullSecrets:             pullsecrets,
-				AutomountServiceAccountToken: requestedSubject.AutomountServiceAccountToken,
-			})
+		// Service Accounts without a namespace are created along with the Role Bindings
+		if requestedSubject.Kind == "ServiceAccount" && requestedSubject.Namespace != "" {
+			p.parseServiceAccount(requestedSubject, requestedSubject.Namespace, rbacBinding.Name)
 		}
 	}
 
 	if rbacBinding.ClusterRoleBindings != nil {
-		for _, requestedCRB := range rbacBinding.ClusterRoleBindings {
-			err := p.parseClusterRoleBinding(requestedCRB, rbacBinding.Subjects, namePrefix)
+		for i, requestedCRB := range rbacBinding.ClusterRoleBindings {
+			if schedule := p.checkSchedule(requestedCRB.NotBefore, requestedCRB.ExpiresAt); schedule != nil {
+				p.setInactiveEntry(rbacBinding.Name, fmt.Sprintf("clusterRoleBindings[%d]", i), schedule)
+				continue
+			}
+			if violation := p.clusterRoleViolation(requestedCRB.ClusterRole); violation != "" {
+				p.setViolation(namePrefix, fmt.Sprintf("clusterRoleBindings[%d]: %v", i, violation))
+				continue
+			}
+			err := p.parseClusterRoleBinding(requestedCRB, subjects, namePrefix)
 			if err != nil {
+				p.truncate(crbCount, rbCount, roleCount, saCount, secretCount)
 				return err
 			}
 		}
 	}
 
 	if rbacBinding.RoleBindings != nil {
-		for _, requestedRB := range rbacBinding.RoleBindings {
-			err := p.parseRoleBinding(requestedRB, rbacBinding.Subjects, namePrefix, namespaces)
+		for i, requestedRB := range rbacBinding.RoleBindings {
+			if schedule := p.checkSchedule(requestedRB.NotBefore, requestedRB.ExpiresAt); schedule != nil {
+				p.setInactiveEntry(rbacBinding.Name, fmt.Sprintf("roleBindings[%d]", i), schedule)
+				continue
+			}
+			if violation := p.roleBindingViolation(requestedRB); violation != "" {
+				p.setViolation(namePrefix, fmt.Sprintf("roleBindings[%d]: %v", i, violation))
+				continue
+			}
+			err := p.parseRoleBinding(requestedRB, subjects, namePrefix, namespaces)
 			if err != nil {
+				p.truncate(crbCount, rbCount, roleCount, saCount, secretCount)
 				return err
 			}
 		}
 	}
+
+	for _, requestedSubject := range rbacBinding.Subjects {
+		if requestedSubject.Kind != "ServiceAccount" || requestedSubject.Namespace != "" {
+			continue
+		}
+		for _, rb := range p.parsedRoleBindings[rbCount:] {
+			exists := slices.ContainsFunc(p.parsedServiceAccounts[saCount:], func(sa v1.ServiceAccount) bool {
+				return sa.Name == requestedSubject.Name && sa.Namespace == rb.Namespace
+			})
+			if !exists {
+				p.parseServiceAccount(requestedSubject, rb.Namespace, rbacBinding.Name)
+			}
+		}
+	}
+
+	for i := range p.parsedClusterRoleBindings[crbCount:] {
+		crb := &p.parsedClusterRoleBindings[crbCount+i]
+		crb.Annotations = map[string]string{BindingAnnotationKey: rbacBinding.Name}
+		p.setBindingName("ClusterRoleBinding", &crb.ObjectMeta, rbacBinding.Name)
+	}
+	for i := range p.parsedRoleBindings[rbCount:] {
+		rb := &p.parsedRoleBindings[rbCount+i]
+		rb.Annotations = map[string]string{BindingAnnotationKey: rbacBinding.Name}
+		p.setBindingName("RoleBinding", &rb.ObjectMeta, rbacBinding.Name)
+	}
+	for i := range p.parsedRoles[roleCount:] {
+		role := &p.parsedRoles[roleCount+i]
+		role.Annotations = map[string]string{BindingAnnotationKey: rbacBinding.Name}
+		p.setBindingName("Role", &role.ObjectMeta, rbacBinding.Name)
+	}
+
 	return nil
 }
 
+// parseServiceAccount adds a Service Account for a subject of an RBACBinding
+//
+//	to the given namespace, along with its token Secrets
+func (p *Parser) parseServiceAccount(requestedSubject rbacmanagerv1beta1.Subject, namespace, bindingName string) {
+	pullsecrets := []v1.LocalObjectReference{}
+	for _, secret := range requestedSubject.ImagePullSecrets {
+		pullsecrets = append(pullsecrets, v1.LocalObjectReference{Name: secret})
+	}
+	annotations := make(map[string]string)
+	maps.Copy(annotations, requestedSubject.Annotations)
+	managedPullSecrets := strings.Join(requestedSubject.ImagePullSecrets, ",")
+	annotations[ManagedPullSecretsAnnotationKey] = managedPullSecrets
+	annotations[ManagedLabelsAnnotationKey] = strings.Join(slices.Sorted(maps.Keys(requestedSubject.Labels)), ",")
+	annotations[ManagedAnnotationsAnnotationKey] = strings.Join(slices.Sorted(maps.Keys(requestedSubject.Annotations)), ",")
+	annotations[BindingAnnotationKey] = bindingName
+	sa := v1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            requestedSubject.Name,
+			Namespace:       namespace,
+			OwnerReferences: p.ownerRefs,
+			Labels:          labels.Merge(requestedSubject.Labels, kube.Labels),
+			Annotations:     annotations,
+		},
+		ImagePullSecrets:             pullsecrets,
+		AutomountServiceAccountToken: requestedSubject.AutomountServiceAccountToken,
+	}
+	p.setBindingName("ServiceAccount", &sa.ObjectMeta, bindingName)
+	p.parsedServiceAccounts = append(p.parsedServiceAccounts, sa)
+
+	// Kubernetes fills in the token of these Secrets
+	for _, secretName := range requestedSubject.TokenSecrets {
+		secret := v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            secretName,
+				Namespace:       namespace,
+				OwnerReferences: p.ownerRefs,
+				Labels:          kube.Labels,
+				Annotations: map[string]string{
+					v1.ServiceAccountNameKey: requestedSubject.Name,
+					BindingAnnotationKey:     bindingName,
+				},
+			},
+			Type: v1.SecretTypeServiceAccountToken,
+		}
+		p.setBindingName("Secret", &secret.ObjectMeta, bindingName)
+		p.parsedSecrets = append(p.parsedSecrets, secret)
+	}
+}
+
+// truncate drops resources parsed from an RBACBinding that turned out to be invalid
+func (p *Parser) truncate(crbCount, rbCount, roleCount, saCount, secretCount int) {
+	p.parsedClusterRoleBindings = p.parsedClusterRoleBindings[:crbCount]
+	p.parsedRoleBindings = p.parsedRoleBindings[:rbCount]
+	p.parsedRoles = p.parsedRoles[:roleCount]
+	p.parsedServiceAccounts = p.parsedServiceAccounts[:saCount]
+	p.parsedSecrets = p.parsedSecrets[:secretCount]
+}
+
 func (p *Parser) parseClusterRoleBinding(
 	crb rbacmanagerv1beta1.ClusterRoleBinding, subjects []rbacmanagerv1beta1.Subject, prefix string) error {
 	crbName := fmt.Sprintf("%v-%v", prefix, crb.ClusterRole)
@@ -162,7 +443,7 @@ func (p *Parser) parseRoleBinding(
 
 	objectMeta.Name = fmt.Sprintf("%v-%v", prefix, requestedRoleName)
 
-	if rb.NamespaceSelector.MatchLabels != nil || len(rb.NamespaceSelector.MatchExpressions) > 0 {
+	if hasNamespaceSelector(&rb) {
 		slog.Debug("Processing Namespace Selector", "selector", rb.NamespaceSelector)
 
 		selector, err := metav1.LabelSelectorAsSelector(&rb.NamespaceSelector)
@@ -171,26 +452,71 @@ func (p *Parser) parseRoleBinding(
 			return err
 		}
 
+		// Label and name selectors must both match when they are combined
+		nameMatches, err := namespaceNameMatcher(rb.NamespaceNames)
+		if err != nil {
+			slog.Info("Error parsing namespace names", "error", err)
+			return err
+		}
+
+		// Roles are namespaced, so only namespaces that contain the Role are
+		// bound unless the Role is created from a template
+		var roleNamespaces map[string]bool
+		if roleRef.Kind == "Role" && rb.RoleTemplate == nil {
+			roleNamespaces, err = p.roleNamespaces(rb.Role)
+			if err != nil {
+				return err
+			}
+		}
+
+		selected := []string{}
 		for _, namespace := range namespaces.Items {
 			// Lazy way to marshal map[] of labels in to a Set, which we can then match on.
-			if selector.Matches(labels.Merge(namespace.Labels, namespace.Labels)) {
-				slog.Debug("Adding Role Binding With Dynamic Namespace", "namespace", namespace.Name)
-
-				om := objectMeta
-				om.Namespace = namespace.Name
-				subs := managerSubjectsToRbacSubjects(subjects)
-
-				p.parsedRoleBindings = append(p.parsedRoleBindings, rbacv1.RoleBinding{
-					ObjectMeta: om,
-					RoleRef:    roleRef,
-					Subjects:   subs,
-				})
+			if selector.Matches(labels.Merge(namespace.Labels, namespace.Labels)) && nameMatches(namespace.Name) {
+				if violation := p.namespaceViolation(namespace.Name); violation != "" {
+					slog.Info("Skipping namespace forbidden by policy", "namespace", namespace.Name, "violation", violation)
+					p.skipNamespace(prefix, namespace.Name)
+					continue
+				}
+				selected = append(selected, namespace.Name)
 			}
 		}
 
+		if violation := p.selectedNamespacesViolation(len(selected)); violation != "" {
+			p.setViolation(prefix, fmt.Sprintf("roleBinding for %v %q: %v", roleRef.Kind, roleRef.Name, violation))
+			return nil
+		}
+
+		for _, namespace := range selected {
+			om := objectMeta
+			om.Namespace = namespace
+
+			if roleRef.Kind == "Role" {
+				if roleNamespaces != nil && !roleNamespaces[namespace] {
+					slog.Info("Skipping namespace without requested Role", "role", rb.Role, "namespace", namespace)
+					p.skipNamespace(prefix, namespace)
+					continue
+				}
+				om.Name = fmt.Sprintf("%v-%v-%v", prefix, rb.Role, namespace)
+			}
+
+			p.parseRoleTemplate(rb, namespace)
+
+			slog.Debug("Adding Role Binding With Dynamic Namespace", "namespace", namespace)
+			subs := subjectsInNamespace(managerSubjectsToRbacSubjects(subjects), namespace)
+
+			p.parsedRoleBindings = append(p.parsedRoleBindings, rbacv1.RoleBinding{
+				ObjectMeta: om,
+				RoleRef:    roleRef,
+				Subjects:   subs,
+			})
+		}
+
 	} else if rb.Namespace != "" {
 		objectMeta.Namespace = rb.Namespace
-		subs := managerSubjectsToRbacSubjects(subjects)
+		subs := subjectsInNamespace(managerSubjectsToRbacSubjects(subjects), rb.Namespace)
+
+		p.parseRoleTemplate(rb, rb.Namespace)
 
 		p.parsedRoleBindings = append(p.parsedRoleBindings, rbacv1.RoleBinding{
 			ObjectMeta: objectMeta,
@@ -205,6 +531,26 @@ func (p *Parser) parseRoleBinding(
 	return nil
 }
 
+// parseRoleTemplate adds the Role a Role Binding creates from its template to
+//
+//	the given namespace, if it has one
+func (p *Parser) parseRoleTemplate(rb rbacmanagerv1beta1.RoleBinding, namespace string) {
+	if rb.RoleTemplate == nil {
+		return
+	}
+
+	slog.Debug("Adding Role from template", "role", rb.Role, "namespace", namespace)
+	p.parsedRoles = append(p.parsedRoles, rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            rb.Role,
+			Namespace:       namespace,
+			OwnerReferences: p.ownerRefs,
+			Labels:          kube.Labels,
+		},
+		Rules: rb.RoleTemplate.Rules,
+	})
+}
+
 func (p *Parser) hasNamespaceSelectors(rbacDef *rbacmanagerv1beta1.RBACDefinition) bool {
 	for _, rbacBinding := range rbacDef.RBACBindings {
 		for _, roleBinding := range rbacBinding.RoleBindings {
@@ -216,37 +562,196 @@ func (p *Parser) hasNamespaceSelectors(rbacDef *rbacmanagerv1beta1.RBACDefinitio
 				if roleBinding.NamespaceSelector.MatchExpressions != nil {
 					return true
 				}
+				if roleBinding.NamespaceNames != nil {
+					return true
+				}
 			}
 		}
 	}
 	return false
 }
 
-func (p *Parser) parseClusterRoleBindings(rbacDef *rbacmanagerv1beta1.RBACDefinition) {
-	for _, rbacBinding := range rbacDef.RBACBindings {
-		for _, clusterRoleBinding := range rbacBinding.ClusterRoleBindings {
-			namePrefix := rdNamePrefix(rbacDef, &rbacBinding)
-			_ = p.parseClusterRoleBinding(clusterRoleBinding, rbacBinding.Subjects, namePrefix)
+// roleNamespaces returns the namespaces that contain a Role with the given name
+func (p *Parser) roleNamespaces(roleName string) (map[string]bool, error) {
+	roles, err := p.listRolesNamed(roleName)
+	if err != nil {
+		slog.Debug("Error listing roles", "role", roleName, "error", err)
+		return nil, err
+	}
+
+	roleNamespaces := map[string]bool{}
+	for _, role := range roles.Items {
+		if role.Name == roleName {
+			roleNamespaces[role.Namespace] = true
 		}
 	}
+	return roleNamespaces, nil
 }
 
-func (p *Parser) parseRoleBindings(rbacDef *rbacmanagerv1beta1.RBACDefinition, namespaces *v1.NamespaceList) {
-	for _, rbacBinding := range rbacDef.RBACBindings {
-		for _, roleBinding := range rbacBinding.RoleBindings {
-			namePrefix := rdNamePrefix(rbacDef, &rbacBinding)
-			_ = p.parseRoleBinding(roleBinding, rbacBinding.Subjects, namePrefix, namespaces)
+// skipNamespace records a selected namespace that could not be bound for the
+//
+//	RBACBinding with the given name prefix
+func (p *Parser) skipNamespace(namePrefix, namespace string) {
+	if p.skippedNamespaces == nil {
+		p.skippedNamespaces = map[string][]string{}
+	}
+	p.skippedNamespaces[namePrefix] = append(p.skippedNamespaces[namePrefix], namespace)
+}
+
+// setBindingName records the RBACBinding a parsed resource was generated from
+func (p *Parser) setBindingName(kind string, objectMeta *metav1.ObjectMeta, bindingName string) {
+	if p.bindingNames == nil {
+		p.bindingNames = map[string]string{}
+	}
+	p.bindingNames[objectKey(kind, objectMeta)] = bindingName
+}
+
+// setInvalidBinding records an RBACBinding that could not be parsed
+func (p *Parser) setInvalidBinding(rbacBinding rbacmanagerv1beta1.RBACBinding, namePrefix string, err error) *BindingError {
+	if p.invalidBindings == nil {
+		p.invalidBindings = map[string]invalidBinding{}
+	}
+	delete(p.skippedNamespaces, namePrefix)
+	delete(p.inactiveEntries, rbacBinding.Name)
+	delete(p.violations, namePrefix)
+	bindingErr := &BindingError{Binding: rbacBinding.Name, Err: err}
+	p.invalidBindings[rbacBinding.Name] = invalidBinding{
+		rbacBinding: rbacBinding,
+		namePrefix:  namePrefix,
+		err:         bindingErr,
+	}
+	return bindingErr
+}
+
+// retains returns true if an existing resource was generated from an
+//
+//	RBACBinding that could not be parsed. These resources are kept as they
+//	are until the RBACBinding is valid again.
+func (p *Parser) retains(kind string, objectMeta *metav1.ObjectMeta) bool {
+	if len(p.invalidBindings) == 0 {
+		return false
+	}
+
+	if bindingName, ok := objectMeta.Annotations[BindingAnnotationKey]; ok {
+		_, invalid := p.invalidBindings[bindingName]
+		return invalid
+	}
+
+	// Resources created before the binding annotation was added, Roles created
+	// from a template and token Secrets have always had it
+	if kind == "Role" || kind == "Secret" {
+		return false
+	}
+	for _, invalid := range p.invalidBindings {
+		if kind == "ServiceAccount" {
+			for _, subject := range invalid.rbacBinding.Subjects {
+				if subject.Kind == rbacv1.ServiceAccountKind && subject.Name == objectMeta.Name && subject.Namespace == objectMeta.Namespace {
+					return true
+				}
+			}
+		} else if invalid.bindingNames(kind).Has(objectMeta.Name) {
+			return true
 		}
 	}
+
+	return false
+}
+
+// bindingNames returns the names of the bindings of a kind generated from an
+//
+//	invalid RBACBinding, other RBACBindings may share its name prefix
+func (invalid invalidBinding) bindingNames(kind string) sets.Set[string] {
+	names := sets.New[string]()
+	switch kind {
+	case "ClusterRoleBinding":
+		for _, crb := range invalid.rbacBinding.ClusterRoleBindings {
+			names.Insert(fmt.Sprintf("%v-%v", invalid.namePrefix, crb.ClusterRole))
+		}
+	case "RoleBinding":
+		for _, rb := range invalid.rbacBinding.RoleBindings {
+			if rb.ClusterRole != "" {
+				names.Insert(fmt.Sprintf("%v-%v", invalid.namePrefix, rb.ClusterRole))
+			} else if rb.Role != "" {
+				names.Insert(fmt.Sprintf("%v-%v-%v", invalid.namePrefix, rb.Role, rb.Namespace))
+			}
+		}
+	}
+	return names
+}
+
+func objectKey(kind string, objectMeta *metav1.ObjectMeta) string {
+	return fmt.Sprintf("%v/%v/%v", kind, objectMeta.Namespace, objectMeta.Name)
 }
 
 func rdNamePrefix(rbacDef *rbacmanagerv1beta1.RBACDefinition, rbacBinding *rbacmanagerv1beta1.RBACBinding) string {
 	return fmt.Sprintf("%v-%v", rbacDef.Name, rbacBinding.Name)
 }
 
+// bindingSubjects returns the subjects of an RBACBinding with the names listed
+//
+//	in ConfigMaps expanded, along with the members of the SubjectSets it refers
+//	to. Only Service Accounts listed in the RBACBinding itself are created,
+//	members of SubjectSets are just bound.
+func (p *Parser) bindingSubjects(rbacBinding rbacmanagerv1beta1.RBACBinding) ([]rbacmanagerv1beta1.Subject, error) {
+	subjects := []rbacmanagerv1beta1.Subject{}
+	members := []rbacv1.Subject{}
+
+	for _, subject := range rbacBinding.Subjects {
+		if subject.FromConfigMap == nil {
+			subjects = append(subjects, subject)
+			continue
+		}
+		expanded, err := p.configMapSubjects(subject)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, expanded...)
+	}
+
+	if len(rbacBinding.SubjectSets) > 0 {
+		if err := p.loadSubjectSets(); err != nil {
+			return nil, err
+		}
+		err := p.expandSubjectSets(rbacBinding.SubjectSets, map[string]bool{}, map[string]bool{}, &members)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, member := range members {
+		duplicate := false
+		for _, subject := range subjects {
+			if sameSubject(subject.Subject, member) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			subjects = append(subjects, rbacmanagerv1beta1.Subject{Subject: member})
+		}
+	}
+	return subjects, nil
+}
+
+// subjectsInNamespace places Service Account subjects without a namespace in
+//
+//	the namespace of the Role Binding they are bound by
+func subjectsInNamespace(subjects []rbacv1.Subject, namespace string) []rbacv1.Subject {
+	for i := range subjects {
+		if subjects[i].Kind == rbacv1.ServiceAccountKind && subjects[i].Namespace == "" {
+			subjects[i].Namespace = namespace
+		}
+	}
+	return subjects
+}
+
 func managerSubjectsToRbacSubjects(subjects []rbacmanagerv1beta1.Subject) []rbacv1.Subject {
 	var subs []rbacv1.Subject
 	for _, sub := range subjects {
+		// Subjects from ConfigMaps are expanded by bindingSubjects
+		if sub.FromConfigMap != nil {
+			continue
+		}
 		subs = append(subs, rbacv1.Subject{
 			Kind:      sub.Kind,
 			APIGroup:  sub.APIGroup,
@@ -17,23 +17,25 @@ package reconciler
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 
 	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
 )
 
 func TestParseEmpty(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	testEmpty(t, client, "empty-example")
 }
 
 func TestParseStandard(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDef.Name = "rbac-config"
 
@@ -130,7 +132,7 @@ func TestParseStandard(t *testing.T) {
 }
 
 func TestParseLabels(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDef.Name = "rbac-config"
 
@@ -234,7 +236,7 @@ func TestParseLabels(t *testing.T) {
 }
 
 func TestParseMissingNamespace(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDef.Name = "rbac-config"
 
@@ -267,6 +269,586 @@ func TestParseMissingNamespace(t *testing.T) {
 	newParseTest(t, client, rbacDef, []rbacv1.RoleBinding{}, []rbacv1.ClusterRoleBinding{}, []corev1.ServiceAccount{})
 }
 
+func TestParseInvalidBinding(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "dev-bot",
+				Namespace: "bots",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "edit",
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "admin",
+		}},
+	}, {
+		Name: "ops",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: "sue",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+	}}
+
+	p := Parser{Clientset: client}
+	err := p.Parse(rbacDef)
+
+	var bindingErr *BindingError
+	assert.ErrorAs(t, err, &bindingErr)
+	assert.Equal(t, "devs", bindingErr.Binding)
+
+	expectParsedSA(t, p, []corev1.ServiceAccount{})
+	expectParsedRB(t, p, []rbacv1.RoleBinding{})
+	expectParsedCRB(t, p, []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rbac-config-ops-view",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "view",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind: rbacv1.UserKind,
+			Name: "sue",
+		}},
+	}})
+	assert.Equal(t, "ops", p.parsedClusterRoleBindings[0].Annotations[BindingAnnotationKey])
+
+	assert.True(t, p.retains("ClusterRoleBinding", &metav1.ObjectMeta{Name: "rbac-config-devs-edit"}))
+	assert.True(t, p.retains("ServiceAccount", &metav1.ObjectMeta{Name: "dev-bot", Namespace: "bots"}))
+	assert.True(t, p.retains("RoleBinding", &metav1.ObjectMeta{Name: "other", Annotations: map[string]string{BindingAnnotationKey: "devs"}}))
+	assert.False(t, p.retains("ClusterRoleBinding", &metav1.ObjectMeta{Name: "rbac-config-ops-edit"}))
+	assert.False(t, p.retains("ClusterRoleBinding", &metav1.ObjectMeta{Name: "rbac-config-devs-ops-edit"}))
+	assert.False(t, p.retains("RoleBinding", &metav1.ObjectMeta{Name: "rbac-config-devs-edit"}))
+	assert.False(t, p.retains("ClusterRoleBinding", &metav1.ObjectMeta{Name: "rbac-config-devs-edit", Annotations: map[string]string{BindingAnnotationKey: "ops"}}))
+}
+
+func TestParseRoleNamespaceSelector(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	createNamespace(t, client, "web", map[string]string{"app": "web", "team": "devs"})
+	createNamespace(t, client, "api", map[string]string{"app": "api", "team": "devs"})
+	createNamespace(t, client, "db", map[string]string{"app": "db", "team": "db"})
+	createRole(t, client, "web", "deployer")
+	createRole(t, client, "db", "deployer")
+	createRole(t, client, "api", "viewer")
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: "joe",
+			},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Role:              "deployer",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "devs"}},
+		}},
+	}}
+
+	p := Parser{Clientset: client}
+	err := p.Parse(rbacDef)
+	assert.NoError(t, err)
+
+	expectParsedRB(t, p, []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rbac-config-devs-deployer-web",
+			Namespace: "web",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "Role",
+			Name: "deployer",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind: rbacv1.UserKind,
+			Name: "joe",
+		}},
+	}})
+	assert.Equal(t, []string{"api"}, p.skippedNamespaces["rbac-config-devs"])
+}
+
+func TestParseNamespaceNames(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	createNamespace(t, client, "team-a-web", map[string]string{"env": "prod"})
+	createNamespace(t, client, "team-a-api", map[string]string{"env": "prod"})
+	createNamespace(t, client, "team-a-staging", map[string]string{"env": "staging"})
+	createNamespace(t, client, "team-b-web", map[string]string{"env": "prod"})
+	createNamespace(t, client, "db-staging", map[string]string{"env": "staging"})
+
+	subjects := []rbacmanagerv1beta1.Subject{{
+		Subject: rbacv1.Subject{
+			Kind: rbacv1.UserKind,
+			Name: "joe",
+		},
+	}}
+	rbacSubjects := []rbacv1.Subject{{
+		Kind: rbacv1.UserKind,
+		Name: "joe",
+	}}
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:     "globs",
+		Subjects: subjects,
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "edit",
+			NamespaceNames: &rbacmanagerv1beta1.NamespaceNameSelector{
+				Include: []string{"team-a-*"},
+				Exclude: []string{"*-staging"},
+			},
+		}},
+	}, {
+		Name:     "regex",
+		Subjects: subjects,
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "view",
+			NamespaceNames: &rbacmanagerv1beta1.NamespaceNameSelector{
+				Type:    rbacmanagerv1beta1.NamespaceNameRegex,
+				Include: []string{"team-(a|b)-web"},
+			},
+		}},
+	}, {
+		Name:     "combined",
+		Subjects: subjects,
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole:       "admin",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "staging"}},
+			NamespaceNames: &rbacmanagerv1beta1.NamespaceNameSelector{
+				Exclude: []string{"db-*"},
+			},
+		}},
+	}}
+
+	newParseTest(t, client, rbacDef, []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "rbac-config-globs-edit", Namespace: "team-a-web"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		Subjects:   rbacSubjects,
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "rbac-config-globs-edit", Namespace: "team-a-api"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		Subjects:   rbacSubjects,
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "rbac-config-regex-view", Namespace: "team-a-web"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		Subjects:   rbacSubjects,
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "rbac-config-regex-view", Namespace: "team-b-web"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		Subjects:   rbacSubjects,
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "rbac-config-combined-admin", Namespace: "team-a-staging"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "admin"},
+		Subjects:   rbacSubjects,
+	}}, []rbacv1.ClusterRoleBinding{}, []corev1.ServiceAccount{})
+
+	p := Parser{Clientset: client}
+	assert.True(t, p.hasNamespaceSelectors(&rbacDef))
+}
+
+func TestParseSchedule(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	lastWeek := metav1.NewTime(now.AddDate(0, 0, -7))
+	yesterday := metav1.NewTime(now.AddDate(0, 0, -1))
+	tomorrow := metav1.NewTime(now.AddDate(0, 0, 1))
+	nextWeek := metav1.NewTime(now.AddDate(0, 0, 7))
+
+	subjects := []rbacmanagerv1beta1.Subject{{
+		Subject: rbacv1.Subject{
+			Kind: rbacv1.UserKind,
+			Name: "joe",
+		},
+	}}
+	rbacSubjects := []rbacv1.Subject{{
+		Kind: rbacv1.UserKind,
+		Name: "joe",
+	}}
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:      "break-glass",
+		Subjects:  subjects,
+		NotBefore: &yesterday,
+		ExpiresAt: &tomorrow,
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "cluster-admin",
+		}, {
+			ClusterRole: "view",
+			ExpiresAt:   &yesterday,
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "edit",
+			Namespace:   "web",
+			NotBefore:   &nextWeek,
+		}},
+	}, {
+		Name:      "expired",
+		Subjects:  subjects,
+		ExpiresAt: &lastWeek,
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "admin",
+		}},
+	}, {
+		Name:      "upcoming",
+		Subjects:  subjects,
+		NotBefore: &nextWeek,
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "edit",
+		}},
+	}}
+
+	p := Parser{Clientset: client, now: now}
+	err := p.Parse(rbacDef)
+	assert.NoError(t, err)
+
+	expectParsedCRB(t, p, []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "rbac-config-break-glass-cluster-admin"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects:   rbacSubjects,
+	}})
+	expectParsedRB(t, p, []rbacv1.RoleBinding{})
+
+	assert.Equal(t, reasonExpired, p.inactiveBindings["expired"].reason)
+	assert.Equal(t, reasonNotYetActive, p.inactiveBindings["upcoming"].reason)
+	assert.Len(t, p.inactiveEntries["break-glass"], 2)
+	assert.Equal(t, map[string]int{reasonExpired: 2, reasonNotYetActive: 2}, p.inactiveCounts())
+	assert.Equal(t, tomorrow.Time, *p.nextChange)
+}
+
+func TestParsePolicies(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	createNamespace(t, client, "kube-system", map[string]string{"env": "prod"})
+	createNamespace(t, client, "web", map[string]string{"env": "prod"})
+	createNamespace(t, client, "api", map[string]string{"env": "prod"})
+	createNamespace(t, client, "db", map[string]string{"env": "prod"})
+
+	maxSelected := 2
+	policies := []rbacmanagerv1beta1.RBACManagerPolicy{{
+		ObjectMeta:         metav1.ObjectMeta{Name: "baseline"},
+		DeniedClusterRoles: []string{"cluster-admin"},
+		ForbiddenSubjects: []rbacv1.Subject{
+			{Kind: rbacv1.GroupKind, Name: "system:unauthenticated"},
+			{Kind: rbacv1.ServiceAccountKind, Name: "default"},
+		},
+		ForbiddenNamespaces:   []string{"kube-system"},
+		MaxSelectedNamespaces: &maxSelected,
+	}, {
+		ObjectMeta:          metav1.ObjectMeta{Name: "allowed"},
+		AllowedClusterRoles: []string{"cluster-admin", "view", "edit"},
+	}}
+
+	subjects := []rbacmanagerv1beta1.Subject{{
+		Subject: rbacv1.Subject{
+			Kind: rbacv1.UserKind,
+			Name: "joe",
+		},
+	}}
+	rbacSubjects := []rbacv1.Subject{{
+		Kind: rbacv1.UserKind,
+		Name: "joe",
+	}}
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:     "devs",
+		Subjects: subjects,
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "cluster-admin",
+		}, {
+			ClusterRole: "admin",
+		}, {
+			ClusterRole: "view",
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "edit",
+			Namespace:   "kube-system",
+		}, {
+			ClusterRole:       "edit",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		}},
+	}, {
+		Name: "anonymous",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:unauthenticated"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+	}, {
+		Name: "bots",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "kube-system"},
+		}, {
+			Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "default", Namespace: "web"},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "view",
+			Namespace:   "web",
+		}},
+	}}
+
+	p := Parser{Clientset: client, policies: policies}
+	err := p.Parse(rbacDef)
+	assert.NoError(t, err)
+
+	expectParsedCRB(t, p, []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "rbac-config-devs-view"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		Subjects:   rbacSubjects,
+	}})
+	expectParsedRB(t, p, []rbacv1.RoleBinding{})
+	assert.Empty(t, p.ServiceAccounts())
+
+	assert.Equal(t, []string{
+		`clusterRoleBindings[0]: ClusterRole "cluster-admin" is denied by policy "baseline"`,
+		`clusterRoleBindings[1]: ClusterRole "admin" is not allowed by policy "allowed"`,
+		`roleBindings[0]: namespace "kube-system" is forbidden by policy "baseline"`,
+		`roleBinding for ClusterRole "edit": 3 namespaces are selected, policy "baseline" allows at most 2`,
+	}, p.violations["rbac-config-devs"])
+	assert.Equal(t, []string{"kube-system"}, p.skippedNamespaces["rbac-config-devs"])
+	assert.Equal(t, []string{`Group "system:unauthenticated" is forbidden by policy "baseline"`}, p.violations["rbac-config-anonymous"])
+	assert.Equal(t, []string{
+		`ServiceAccount "ci" is in namespace "kube-system" which is forbidden by policy "baseline"`,
+		`ServiceAccount "default" is forbidden by policy "baseline"`,
+	}, p.violations["rbac-config-bots"])
+}
+
+func TestParsePoliciesRoleTemplate(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	createNamespace(t, client, "web", map[string]string{"team": "dev"})
+
+	readSecrets := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}}}
+	deploy := []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "update"}}}
+	policies := []rbacmanagerv1beta1.RBACManagerPolicy{{
+		ObjectMeta:  metav1.ObjectMeta{Name: "baseline"},
+		DeniedRules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+	}}
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Role:              "secret-reader",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "dev"}},
+			RoleTemplate:      &rbacmanagerv1beta1.RoleTemplate{Rules: readSecrets},
+		}, {
+			Role:              "deployer",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "dev"}},
+			RoleTemplate:      &rbacmanagerv1beta1.RoleTemplate{Rules: deploy},
+		}},
+	}}
+
+	p := Parser{Clientset: client, policies: policies}
+	err := p.Parse(rbacDef)
+	assert.NoError(t, err)
+
+	// the denied template creates neither its Roles nor their Role Bindings
+	roles := []string{}
+	for _, role := range p.Roles() {
+		roles = append(roles, role.Namespace+"/"+role.Name)
+	}
+	assert.Equal(t, []string{"web/deployer"}, roles)
+	rbs := []string{}
+	for _, rb := range p.RoleBindings() {
+		rbs = append(rbs, rb.Namespace+"/"+rb.Name)
+	}
+	assert.Equal(t, []string{"web/rbac-config-devs-deployer-web"}, rbs)
+	assert.Equal(t, []string{
+		`roleBindings[0]: rules grant deniedRules[0] of policy "baseline"`,
+	}, p.violations["rbac-config-devs"])
+}
+
+func TestParseRoles(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	rules := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}}
+	rbacDef.ClusterRoles = []rbacmanagerv1beta1.ClusterRole{{
+		Name:   "secret-reader",
+		Labels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-view": "true"},
+		Rules:  rules,
+	}}
+	rbacDef.Roles = []rbacmanagerv1beta1.Role{{
+		Name:      "secret-reader",
+		Namespace: "web",
+		Rules:     rules,
+	}}
+
+	p := Parser{Clientset: client}
+	err := p.Parse(rbacDef)
+	assert.NoError(t, err)
+
+	assert.Len(t, p.ClusterRoles(), 1)
+	assert.Equal(t, map[string]string{
+		"rbac.authorization.k8s.io/aggregate-to-view": "true",
+		"rbac-manager": "reactiveops",
+	}, p.ClusterRoles()[0].Labels)
+	assert.Len(t, p.Roles(), 1)
+	assert.Equal(t, "web", p.Roles()[0].Namespace)
+	assert.Equal(t, rules, p.Roles()[0].Rules)
+
+	// invalid roles stop everything else from being parsed
+	rbacDef.Roles[0].Namespace = ""
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:                "devs",
+		Subjects:            []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "secret-reader"}},
+	}}
+
+	p = Parser{Clientset: client}
+	err = p.Parse(rbacDef)
+	assert.Error(t, err)
+	assert.Empty(t, p.ClusterRoles())
+	assert.Empty(t, p.ClusterRoleBindings())
+}
+
+func TestParseSubjectSets(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	subjectSets := []rbacmanagerv1beta1.SubjectSet{{
+		ObjectMeta:  metav1.ObjectMeta{Name: "sre"},
+		Subjects:    []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "jane"}, {Kind: rbacv1.UserKind, Name: "joe"}},
+		SubjectSets: []string{"sre-leads", "bots"},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "sre-leads"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "sre-leads"}, {Kind: rbacv1.UserKind, Name: "jane"}},
+	}, {
+		ObjectMeta:  metav1.ObjectMeta{Name: "bots"},
+		Subjects:    []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "ci"}},
+		SubjectSets: []string{"sre-leads"},
+	}, {
+		ObjectMeta:  metav1.ObjectMeta{Name: "loop"},
+		SubjectSets: []string{"loop-back"},
+	}, {
+		ObjectMeta:  metav1.ObjectMeta{Name: "loop-back"},
+		SubjectSets: []string{"loop"},
+	}}
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:                "sre",
+		Subjects:            []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+		SubjectSets:         []string{"sre"},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+	}, {
+		Name:                "missing",
+		SubjectSets:         []string{"nobody"},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+	}, {
+		Name:                "loop",
+		SubjectSets:         []string{"loop"},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+	}}
+
+	p := Parser{Clientset: client}
+	p.LoadSubjectSets = func() (rbacmanagerv1beta1.SubjectSetList, error) {
+		return rbacmanagerv1beta1.SubjectSetList{Items: subjectSets}, nil
+	}
+	err := p.Parse(rbacDef)
+	var bindingErr *BindingError
+	assert.ErrorAs(t, err, &bindingErr)
+	assert.Len(t, p.invalidBindings, 2)
+	assert.ErrorContains(t, p.invalidBindings["missing"].err, `subject set "nobody" not found`)
+	assert.ErrorContains(t, p.invalidBindings["loop"].err, `subject set "loop" includes itself`)
+
+	// members of every included set are bound once, after the subjects of the binding
+	assert.Len(t, p.ClusterRoleBindings(), 1)
+	assert.Equal(t, []rbacv1.Subject{
+		{Kind: rbacv1.UserKind, Name: "joe"},
+		{Kind: rbacv1.UserKind, Name: "jane"},
+		{Kind: rbacv1.GroupKind, Name: "sre-leads"},
+		{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "ci"},
+	}, p.ClusterRoleBindings()[0].Subjects)
+
+	// service accounts in a subject set are not created
+	assert.Empty(t, p.ServiceAccounts())
+
+	rbacDef.RBACBindings = rbacDef.RBACBindings[:1]
+	assert.True(t, ReferencesSubjectSet(&rbacDef, "sre", subjectSets))
+	assert.True(t, ReferencesSubjectSet(&rbacDef, "sre-leads", subjectSets))
+	assert.True(t, ReferencesSubjectSet(&rbacDef, "bots", subjectSets))
+	assert.False(t, ReferencesSubjectSet(&rbacDef, "loop", subjectSets))
+}
+
+func TestParseConfigMapSubjects(t *testing.T) {
+	client := fake.NewClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "teams", Namespace: "identity"},
+		Data: map[string]string{
+			"platform": "# exported from the IdP\njane@example.com\n\njoe@example.com, sue@example.com\n",
+		},
+	})
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "rbac-config"
+
+	fromConfigMap := func(key string) rbacmanagerv1beta1.Subject {
+		return rbacmanagerv1beta1.Subject{
+			Subject:       rbacv1.Subject{Kind: rbacv1.UserKind},
+			FromConfigMap: &rbacmanagerv1beta1.ConfigMapKeyReference{Namespace: "identity", Name: "teams", Key: key},
+		}
+	}
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "platform",
+		Subjects: []rbacmanagerv1beta1.Subject{
+			{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe@example.com"}},
+			fromConfigMap("platform"),
+		},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+	}, {
+		Name:                "missing",
+		Subjects:            []rbacmanagerv1beta1.Subject{fromConfigMap("sre")},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+	}}
+
+	p := Parser{Clientset: client}
+	err := p.Parse(rbacDef)
+	var bindingErr *BindingError
+	assert.ErrorAs(t, err, &bindingErr)
+	assert.ErrorContains(t, p.invalidBindings["missing"].err, `key "sre" not found in ConfigMap identity/teams`)
+
+	assert.Len(t, p.ClusterRoleBindings(), 1)
+	assert.Equal(t, []rbacv1.Subject{
+		{Kind: rbacv1.UserKind, Name: "joe@example.com"},
+		{Kind: rbacv1.UserKind, Name: "jane@example.com"},
+		{Kind: rbacv1.UserKind, Name: "sue@example.com"},
+	}, p.ClusterRoleBindings()[0].Subjects)
+
+	key := types.NamespacedName{Namespace: "identity", Name: "teams"}
+	assert.True(t, ReferencesConfigMap(rbacDef.RBACBindings, "", key))
+	assert.False(t, ReferencesConfigMap(rbacDef.RBACBindings, "", types.NamespacedName{Namespace: "web", Name: "teams"}))
+
+	rbacDef.RBACBindings[1].Subjects[0].FromConfigMap.Namespace = ""
+	assert.True(t, ReferencesConfigMap(rbacDef.RBACBindings[1:], "identity", key))
+}
+
 func TestManagerToRbacSubjects(t *testing.T) {
 	expected := []rbacv1.Subject{
 		{
@@ -287,7 +869,7 @@ func TestManagerToRbacSubjects(t *testing.T) {
 func TestServiceAccountParsing(t *testing.T) {
 	for _, n := range saTestCases {
 		t.Run(n.name, func(t *testing.T) {
-			client := fake.NewSimpleClientset()
+			client := fake.NewClientset()
 			rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 			rbacDef.Name = "rbac-config"
 
@@ -399,3 +981,20 @@ func createNamespace(t *testing.T, client *fake.Clientset, name string, labels m
 		t.Fatalf("Error creating namespace %v", err)
 	}
 }
+
+func createRole(t *testing.T, client *fake.Clientset, namespace, name string) {
+	_, err := client.RbacV1().Roles(namespace).Create(
+		context.TODO(),
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+		},
+		metav1.CreateOptions{},
+	)
+
+	if err != nil {
+		t.Fatalf("Error creating role %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"strconv"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// PlanOnlyAnnotationKey is the annotation that limits reconciliation of an RBAC Definition to recording a plan in its status
+const PlanOnlyAnnotationKey string = "rbacmanager.reactiveops.io/plan-only"
+
+// IsPlanOnly returns true if changes for an RBAC Definition should be planned but not made
+func IsPlanOnly(rbacDef *rbacmanagerv1beta1.RBACDefinition) bool {
+	planOnly, _ := strconv.ParseBool(rbacDef.Annotations[PlanOnlyAnnotationKey])
+	return planOnly
+}
+
+// PlanIsEmpty returns true if a plan does not contain any changes
+func PlanIsEmpty(plan *rbacmanagerv1beta1.Plan) bool {
+	return plan == nil || len(plan.Create) == 0 && len(plan.Update) == 0 && len(plan.Delete) == 0
+}
+
+func plannedChange(kind string, objectMeta *metav1.ObjectMeta, roleRef *rbacv1.RoleRef, subjects []rbacv1.Subject) rbacmanagerv1beta1.PlannedChange {
+	return rbacmanagerv1beta1.PlannedChange{
+		Kind:      kind,
+		Name:      objectMeta.Name,
+		Namespace: objectMeta.Namespace,
+		RoleRef:   roleRef,
+		Subjects:  subjects,
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/metrics"
+)
+
+// Reasons used for the PolicyViolation condition
+const (
+	reasonCompliant       = "Compliant"
+	reasonPolicyViolation = "PolicyViolation"
+)
+
+// loadPolicies fetches the policies that apply to the RBAC Definition being parsed
+func (p *Parser) loadPolicies() error {
+	if p.LoadPolicies == nil {
+		return nil
+	}
+
+	policies, err := p.LoadPolicies()
+	if err != nil {
+		slog.Debug("Error listing RBAC Manager policies", "error", err)
+		return err
+	}
+	p.policies = policies.Items
+	return nil
+}
+
+// clusterRoleViolation describes why a policy forbids binding a ClusterRole,
+//
+//	or returns an empty string if every policy allows it
+func (p *Parser) clusterRoleViolation(clusterRole string) string {
+	for _, policy := range p.policies {
+		if slices.Contains(policy.DeniedClusterRoles, clusterRole) {
+			return fmt.Sprintf("ClusterRole %q is denied by policy %q", clusterRole, policy.Name)
+		}
+		if len(policy.AllowedClusterRoles) > 0 && !slices.Contains(policy.AllowedClusterRoles, clusterRole) {
+			return fmt.Sprintf("ClusterRole %q is not allowed by policy %q", clusterRole, policy.Name)
+		}
+	}
+	return ""
+}
+
+// subjectViolations describes the subjects of an RBACBinding that a policy forbids
+func (p *Parser) subjectViolations(subjects []rbacmanagerv1beta1.Subject) []string {
+	violations := []string{}
+	for _, subject := range subjects {
+		for _, policy := range p.policies {
+			if slices.ContainsFunc(policy.ForbiddenSubjects, func(forbidden rbacv1.Subject) bool {
+				return isForbiddenSubject(forbidden, subject.Subject)
+			}) {
+				violations = append(violations, fmt.Sprintf("%v %q is forbidden by policy %q", subject.Kind, subject.Name, policy.Name))
+				break
+			}
+			if subject.Kind == rbacv1.ServiceAccountKind && slices.Contains(policy.ForbiddenNamespaces, subject.Namespace) {
+				violations = append(violations, fmt.Sprintf("ServiceAccount %q is in namespace %q which is forbidden by policy %q", subject.Name, subject.Namespace, policy.Name))
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// namespaceViolation describes why a policy forbids binding roles in a
+//
+//	namespace, or returns an empty string if every policy allows it
+func (p *Parser) namespaceViolation(namespace string) string {
+	for _, policy := range p.policies {
+		if slices.Contains(policy.ForbiddenNamespaces, namespace) {
+			return fmt.Sprintf("namespace %q is forbidden by policy %q", namespace, policy.Name)
+		}
+	}
+	return ""
+}
+
+// roleBindingViolation describes why a policy forbids a Role Binding entry,
+//
+//	or returns an empty string if every policy allows it. Namespaces selected
+//	by a Role Binding are checked once they are known.
+func (p *Parser) roleBindingViolation(rb rbacmanagerv1beta1.RoleBinding) string {
+	// Only Namespaced RBAC Definitions have a namespace
+	if p.namespace != "" {
+		if errs := validateNamespacedRoleBinding(rb, p.NamespacedClusterRoles, nil); len(errs) > 0 {
+			return errs[0].Detail
+		}
+	}
+	if rb.ClusterRole != "" {
+		if violation := p.clusterRoleViolation(rb.ClusterRole); violation != "" {
+			return violation
+		}
+	}
+	// A role template creates a Role in every selected namespace
+	if rb.RoleTemplate != nil {
+		if violation := p.rulesViolation(rb.RoleTemplate.Rules); violation != "" {
+			return violation
+		}
+	}
+	if rb.Namespace != "" && !hasNamespaceSelector(&rb) {
+		return p.namespaceViolation(rb.Namespace)
+	}
+	return ""
+}
+
+// clusterRoleDefinitionViolation describes why a policy forbids a ClusterRole
+//
+//	defined in the RBAC Definition, or returns an empty string if every
+//	policy allows it
+func (p *Parser) clusterRoleDefinitionViolation(cr rbacmanagerv1beta1.ClusterRole) string {
+	if violation := p.clusterRoleViolation(cr.Name); violation != "" {
+		return violation
+	}
+	// The rules of an aggregated ClusterRole are not known until they are aggregated
+	if cr.AggregationRule != nil {
+		for _, policy := range p.policies {
+			if len(policy.DeniedRules) > 0 {
+				return fmt.Sprintf("aggregation rules are not allowed by policy %q", policy.Name)
+			}
+		}
+	}
+	return p.rulesViolation(cr.Rules)
+}
+
+// roleDefinitionViolation describes why a policy forbids a Role defined in
+//
+//	the RBAC Definition, or returns an empty string if every policy allows it
+func (p *Parser) roleDefinitionViolation(role rbacmanagerv1beta1.Role) string {
+	if violation := p.namespaceViolation(role.Namespace); violation != "" {
+		return violation
+	}
+	return p.rulesViolation(role.Rules)
+}
+
+// rulesViolation describes the first rule a policy denies, or returns an
+//
+//	empty string if every policy allows all of them
+func (p *Parser) rulesViolation(rules []rbacv1.PolicyRule) string {
+	for _, policy := range p.policies {
+		for i, denied := range policy.DeniedRules {
+			if slices.ContainsFunc(rules, func(rule rbacv1.PolicyRule) bool {
+				return ruleGrants(rule, denied)
+			}) {
+				return fmt.Sprintf("rules grant deniedRules[%d] of policy %q", i, policy.Name)
+			}
+		}
+	}
+	return ""
+}
+
+// selectedNamespacesViolation describes why a policy forbids a Role Binding
+//
+//	from selecting the given number of namespaces, or returns an empty string
+//	if every policy allows it
+func (p *Parser) selectedNamespacesViolation(count int) string {
+	for _, policy := range p.policies {
+		if policy.MaxSelectedNamespaces != nil && count > *policy.MaxSelectedNamespaces {
+			return fmt.Sprintf("%d namespaces are selected, policy %q allows at most %d", count, policy.Name, *policy.MaxSelectedNamespaces)
+		}
+	}
+	return ""
+}
+
+// setViolation records part of the RBACBinding with the given name prefix
+//
+//	that was not parsed because a policy forbids it
+func (p *Parser) setViolation(namePrefix, violation string) {
+	if p.violations == nil {
+		p.violations = map[string][]string{}
+	}
+	slog.Warn("Skipping resources that violate a policy", "rbacBinding", namePrefix, "violation", violation)
+	metrics.ErrorCounter.WithLabelValues(metrics.ErrorPolicyViolation).Inc()
+	p.violations[namePrefix] = append(p.violations[namePrefix], violation)
+}
+
+// setRoleViolation records a Role or ClusterRole defined in the RBAC
+//
+//	Definition that was not parsed because a policy forbids it
+func (p *Parser) setRoleViolation(kind, name, violation string) {
+	slog.Warn("Skipping role that violates a policy", "kind", kind, "name", name, "violation", violation)
+	metrics.ErrorCounter.WithLabelValues(metrics.ErrorPolicyViolation).Inc()
+	p.roleViolations = append(p.roleViolations, fmt.Sprintf("%v %q: %v", kind, name, violation))
+}
+
+// ruleGrants returns true if a single rule grants everything a denied rule
+//
+//	lists. Denied rules without resource names match every resource name.
+func ruleGrants(rule, denied rbacv1.PolicyRule) bool {
+	if !grantsAll(rule.Verbs, denied.Verbs) ||
+		!grantsAll(rule.APIGroups, denied.APIGroups) ||
+		!grantsAll(rule.Resources, denied.Resources) ||
+		!grantsAll(rule.NonResourceURLs, denied.NonResourceURLs) {
+		return false
+	}
+	if len(rule.ResourceNames) == 0 {
+		return true
+	}
+	return len(denied.ResourceNames) > 0 && grantsAll(rule.ResourceNames, denied.ResourceNames)
+}
+
+// grantsAll returns true if every value is granted, * grants every value
+func grantsAll(granted, values []string) bool {
+	if slices.Contains(granted, "*") {
+		return true
+	}
+	for _, value := range values {
+		if !slices.Contains(granted, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// isForbiddenSubject returns true if a subject is the forbidden subject.
+//
+//	Forbidden subjects without a namespace match a subject in any namespace.
+func isForbiddenSubject(forbidden, subject rbacv1.Subject) bool {
+	if forbidden.Kind != subject.Kind || forbidden.Name != subject.Name {
+		return false
+	}
+	return forbidden.Namespace == "" || forbidden.Namespace == subject.Namespace
+}
@@ -16,29 +16,67 @@ package reconciler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"reflect"
 	"sync"
 
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
-	"github.com/fairwindsops/rbac-manager/pkg/kube"
 	"github.com/fairwindsops/rbac-manager/pkg/metrics"
 )
 
-// Reconciler creates and deletes Kubernetes resources to achieve the desired state of an RBAC Definition
+// Reconciler applies and deletes Kubernetes resources to achieve the desired state of an RBAC Definition.
+// Reads are served by Reader when it is set, e.g. the cached client of a controller-runtime manager.
 type Reconciler struct {
+	Options
 	Clientset kubernetes.Interface
+	Reader    client.Reader
 	ownerRefs []metav1.OwnerReference
+	managed   rbacmanagerv1beta1.ManagedObjectCounts
+	failures  map[string]error
+	plan      *rbacmanagerv1beta1.Plan
+	pending   []rbacmanagerv1beta1.PlannedChange
+	adopt     bool
+	adopted   []rbacmanagerv1beta1.PlannedChange
+	// ownedServiceAccounts are the Service Accounts this RBAC Definition
+	// applied or already owns, only they get token Secrets
+	ownedServiceAccounts sets.Set[string]
 }
 
 var mux = sync.Mutex{}
 
+// recordOwnedServiceAccount tracks a Service Account this RBAC Definition owns
+func (r *Reconciler) recordOwnedServiceAccount(objectMeta *metav1.ObjectMeta) {
+	if r.ownedServiceAccounts == nil {
+		r.ownedServiceAccounts = sets.New[string]()
+	}
+	r.ownedServiceAccounts.Insert(objectKey("ServiceAccount", objectMeta))
+}
+
+// reset clears what a previous reconciliation recorded, a Reconciler is
+//
+//	reused for every RBAC Definition
+func (r *Reconciler) reset(rbacDef *rbacmanagerv1beta1.RBACDefinition, ownerRefs []metav1.OwnerReference) {
+	r.ownerRefs = ownerRefs
+	r.managed = rbacmanagerv1beta1.ManagedObjectCounts{}
+	r.failures = map[string]error{}
+	r.plan = nil
+	r.pending = nil
+	r.ownedServiceAccounts = nil
+	r.adopt = r.AdoptExisting || IsAdopting(rbacDef)
+	r.adopted = nil
+}
+
 // ReconcileNamespaceChange reconciles relevant portions of RBAC Definitions
 //
 //	after changes to namespaces within the cluster
@@ -46,26 +84,41 @@ func (r *Reconciler) ReconcileNamespaceChange(rbacDef *rbacmanagerv1beta1.RBACDe
 	mux.Lock()
 	defer mux.Unlock()
 
-	r.ownerRefs = rbacDefOwnerRefs(rbacDef)
+	if IsPlanOnly(rbacDef) {
+		return nil
+	}
+
+	r.reset(rbacDef, rbacDefOwnerRefs(rbacDef))
+
+	p := r.newParser()
 
-	p := Parser{
-		Clientset: r.Clientset,
-		ownerRefs: r.ownerRefs,
+	parseErr := p.Parse(*rbacDef)
+	if p.invalidRoles != nil {
+		return nil
+	}
+	if parseErr != nil && len(p.invalidBindings) == 0 {
+		return parseErr
 	}
 
-	err := p.Parse(*rbacDef)
+	err := r.reconcileServiceAccounts(&p)
 	if err != nil {
 		return err
 	}
 
-	err = r.reconcileServiceAccounts(&p.parsedServiceAccounts)
+	err = r.reconcileSecrets(&p)
 	if err != nil {
 		return err
 	}
 
 	if p.hasNamespaceSelectors(rbacDef) {
 		slog.Info("Reconciling namespace", "namespace", namespace.Name, "rbacDefinition", rbacDef.Name)
-		err := r.reconcileRoleBindings(&p.parsedRoleBindings)
+		// Roles created from templates are bound, so they have to exist first
+		err := r.reconcileRoles(&p)
+		if err != nil {
+			return err
+		}
+
+		err = r.reconcileRoleBindings(&p)
 		if err != nil {
 			return err
 		}
@@ -74,84 +127,122 @@ func (r *Reconciler) ReconcileNamespaceChange(rbacDef *rbacmanagerv1beta1.RBACDe
 	return nil
 }
 
-// ReconcileOwners reconciles any RBACDefinitions found in owner references
-func (r *Reconciler) ReconcileOwners(ownerRefs []metav1.OwnerReference, kind string) error {
+// Reconcile creates, updates, or deletes Kubernetes resources to match
+//
+//	the desired state defined in an RBAC Definition. The outcome is recorded
+//	on the status of the RBAC Definition, it is up to the caller to persist it.
+//	RBAC Definitions with the plan-only annotation only have a plan recorded.
+func (r *Reconciler) Reconcile(rbacDef *rbacmanagerv1beta1.RBACDefinition) error {
 	mux.Lock()
 	defer mux.Unlock()
 
-	namespaces, err := r.Clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		slog.Debug("Error listing namespaces", "error", err)
-		return err
-	}
+	slog.Info("Reconciling RBACDefinition", "name", rbacDef.Name)
 
-	for _, ownerRef := range ownerRefs {
-		if ownerRef.Kind == "RBACDefinition" {
-			rbacDef, err := kube.GetRbacDefinition(ownerRef.Name)
-			if err != nil {
-				return err
-			}
+	return r.reconcile(rbacDef, rbacDefOwnerRefs(rbacDef))
+}
 
-			r.ownerRefs = rbacDefOwnerRefs(&rbacDef)
+// ReconcileNamespaced creates, updates, or deletes the Role Bindings and
+//
+//	Service Accounts defined in a Namespaced RBAC Definition. Like Reconcile,
+//	the outcome is recorded on its status for the caller to persist.
+func (r *Reconciler) ReconcileNamespaced(nrd *rbacmanagerv1beta1.NamespacedRBACDefinition) error {
+	mux.Lock()
+	defer mux.Unlock()
 
-			p := Parser{
-				Clientset: r.Clientset,
-				ownerRefs: r.ownerRefs,
-			}
+	slog.Info("Reconciling NamespacedRBACDefinition", "name", nrd.Name, "namespace", nrd.Namespace)
 
-			switch kind {
-			case "RoleBinding":
-				p.parseRoleBindings(&rbacDef, namespaces)
-				return r.reconcileRoleBindings(&p.parsedRoleBindings)
-			case "ClusterRoleBinding":
-				p.parseClusterRoleBindings(&rbacDef)
-				return r.reconcileClusterRoleBindings(&p.parsedClusterRoleBindings)
-			case "ServiceAccount":
-				err := p.Parse(rbacDef)
-				if err != nil {
-					return err
-				}
-				return r.reconcileServiceAccounts(&p.parsedServiceAccounts)
-			}
-		}
+	rbacDef := namespacedRbacDef(nrd)
+	err := r.reconcile(&rbacDef, namespacedRbacDefOwnerRefs(nrd))
+	nrd.Status = rbacDef.Status
+
+	return err
+}
+
+func (r *Reconciler) reconcile(rbacDef *rbacmanagerv1beta1.RBACDefinition, ownerRefs []metav1.OwnerReference) error {
+	r.reset(rbacDef, ownerRefs)
+
+	if IsPlanOnly(rbacDef) {
+		slog.Info("Planning changes only", "name", rbacDef.Name)
+		r.plan = &rbacmanagerv1beta1.Plan{}
+		defer func() {
+			r.plan = nil
+		}()
 	}
-	return nil
+
+	p := r.newParser()
+
+	err := r.reconcileDefinition(&p, rbacDef)
+	r.setStatus(rbacDef, &p, err)
+
+	return err
 }
 
-// Reconcile creates, updates, or deletes Kubernetes resources to match
+// Plan determines the changes required to reach the desired state defined
 //
-//	the desired state defined in an RBAC Definition
-func (r *Reconciler) Reconcile(rbacDef *rbacmanagerv1beta1.RBACDefinition) error {
+//	in an RBAC Definition without making them
+func (r *Reconciler) Plan(rbacDef *rbacmanagerv1beta1.RBACDefinition) (*rbacmanagerv1beta1.Plan, error) {
 	mux.Lock()
 	defer mux.Unlock()
 
-	slog.Info("Reconciling RBACDefinition", "name", rbacDef.Name)
+	r.reset(rbacDef, rbacDefOwnerRefs(rbacDef))
+	r.plan = &rbacmanagerv1beta1.Plan{}
+	defer func() {
+		r.plan = nil
+	}()
+
+	p := r.newParser()
+
+	err := r.reconcileDefinition(&p, rbacDef)
+	if err != nil {
+		return r.plan, err
+	}
 
-	r.ownerRefs = rbacDefOwnerRefs(rbacDef)
+	// There is no status to report an invalid spec on, so it is returned
+	// along with the plan for everything else
+	return r.plan, errors.Join(p.invalidRoles, p.bindingErrs)
+}
 
-	p := Parser{
-		Clientset: r.Clientset,
-		ownerRefs: r.ownerRefs,
+// reconcileDefinition reconciles every valid RBACBinding in an RBAC Definition.
+//
+//	Invalid RBACBindings, ClusterRoles, and Roles are recorded on the Parser
+//	for the InvalidSpec condition, they are not returned as errors. Nothing
+//	is reconciled while a ClusterRole or Role is invalid.
+func (r *Reconciler) reconcileDefinition(p *Parser, rbacDef *rbacmanagerv1beta1.RBACDefinition) error {
+	parseErr := p.Parse(*rbacDef)
+	if p.invalidRoles != nil {
+		return nil
+	}
+	if parseErr != nil && len(p.invalidBindings) == 0 {
+		return parseErr
 	}
 
-	var err error
+	err := r.reconcileServiceAccounts(p)
+	if err != nil {
+		return err
+	}
 
-	err = p.Parse(*rbacDef)
+	err = r.reconcileSecrets(p)
 	if err != nil {
 		return err
 	}
 
-	err = r.reconcileServiceAccounts(&p.parsedServiceAccounts)
+	// Roles are reconciled before the bindings that may refer to them
+	err = r.reconcileClusterRoles(p)
 	if err != nil {
 		return err
 	}
 
-	err = r.reconcileClusterRoleBindings(&p.parsedClusterRoleBindings)
+	err = r.reconcileRoles(p)
 	if err != nil {
 		return err
 	}
 
-	err = r.reconcileRoleBindings(&p.parsedRoleBindings)
+	err = r.reconcileClusterRoleBindings(p)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileRoleBindings(p)
 	if err != nil {
 		return err
 	}
@@ -159,14 +250,17 @@ func (r *Reconciler) Reconcile(rbacDef *rbacmanagerv1beta1.RBACDefinition) error
 	return nil
 }
 
-func (r *Reconciler) reconcileServiceAccounts(requested *[]v1.ServiceAccount) error {
-	existing, err := r.Clientset.CoreV1().ServiceAccounts("").List(context.TODO(), kube.ListOptions)
+func (r *Reconciler) reconcileServiceAccounts(p *Parser) error {
+	requested := &p.parsedServiceAccounts
+
+	existing, err := r.listServiceAccounts()
 	if err != nil {
 		return err
 	}
 
 	matchingServiceAccounts := []v1.ServiceAccount{}
 	serviceAccountsToCreate := []v1.ServiceAccount{}
+	serviceAccountsToUpdate := []v1.ServiceAccount{}
 
 	for _, requestedSA := range *requested {
 		alreadyExists := false
@@ -174,14 +268,30 @@ func (r *Reconciler) reconcileServiceAccounts(requested *[]v1.ServiceAccount) er
 			if saMatches(&existingSA, &requestedSA) {
 				alreadyExists = true
 				matchingServiceAccounts = append(matchingServiceAccounts, existingSA)
+				r.managed.ServiceAccounts++
+				r.recordOwnedServiceAccount(&requestedSA.ObjectMeta)
 				break
 			}
 		}
 
-		if !alreadyExists {
-			serviceAccountsToCreate = append(serviceAccountsToCreate, requestedSA)
-		} else {
+		if alreadyExists {
 			slog.Debug("Service Account already exists", "name", requestedSA.Name)
+			continue
+		}
+
+		// Service Accounts that drifted are updated in place
+		updatable := false
+		for _, existingSA := range existing.Items {
+			if metaMatches(&existingSA.ObjectMeta, &requestedSA.ObjectMeta) {
+				updatable = true
+				serviceAccountsToUpdate = append(serviceAccountsToUpdate, requestedSA)
+				r.recordOwnedServiceAccount(&requestedSA.ObjectMeta)
+				break
+			}
+		}
+
+		if !updatable {
+			serviceAccountsToCreate = append(serviceAccountsToCreate, requestedSA)
 		}
 	}
 
@@ -194,13 +304,30 @@ func (r *Reconciler) reconcileServiceAccounts(requested *[]v1.ServiceAccount) er
 					break
 				}
 			}
+			for _, updatedSA := range serviceAccountsToUpdate {
+				if metaMatches(&existingSA.ObjectMeta, &updatedSA.ObjectMeta) {
+					matchingRequest = true
+					break
+				}
+			}
 
 			if !matchingRequest {
+				if p.retains("ServiceAccount", &existingSA.ObjectMeta) {
+					slog.Info("Retaining Service Account of invalid RBAC Binding", "name", existingSA.Name)
+					continue
+				}
+
+				if r.plan != nil {
+					r.plan.Delete = append(r.plan.Delete, plannedChange("ServiceAccount", &existingSA.ObjectMeta, nil, nil))
+					continue
+				}
+
 				slog.Info("Deleting Service Account", "name", existingSA.Name)
 				err := r.Clientset.CoreV1().ServiceAccounts(existingSA.Namespace).Delete(context.TODO(), existingSA.Name, metav1.DeleteOptions{})
-				if err != nil {
+				if err != nil && !apierrors.IsNotFound(err) {
 					slog.Info("Error deleting Service Account", "name", existingSA.Name, "error", err)
-					metrics.ErrorCounter.Inc()
+					r.recordFailure("ServiceAccount", &existingSA.ObjectMeta, err)
+					metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 				} else {
 					metrics.ChangeCounter.WithLabelValues("serviceaccounts", "delete").Inc()
 				}
@@ -210,13 +337,40 @@ func (r *Reconciler) reconcileServiceAccounts(requested *[]v1.ServiceAccount) er
 		}
 	}
 
+	for _, serviceAccountToUpdate := range serviceAccountsToUpdate {
+		if r.plan != nil {
+			r.plan.Update = append(r.plan.Update, plannedChange("ServiceAccount", &serviceAccountToUpdate.ObjectMeta, nil, nil))
+			continue
+		}
+
+		slog.Info("Updating Service Account", "name", serviceAccountToUpdate.Name, "namespace", serviceAccountToUpdate.Namespace)
+		err := r.applyServiceAccount(&serviceAccountToUpdate)
+		if err != nil {
+			slog.Error("Error updating Service Account", "name", serviceAccountToUpdate.Name, "error", err)
+			r.recordFailure("ServiceAccount", &serviceAccountToUpdate.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			r.managed.ServiceAccounts++
+			metrics.ChangeCounter.WithLabelValues("serviceaccounts", "update").Inc()
+		}
+	}
+
 	for _, serviceAccountToCreate := range serviceAccountsToCreate {
+		if r.plan != nil {
+			r.plan.Create = append(r.plan.Create, plannedChange("ServiceAccount", &serviceAccountToCreate.ObjectMeta, nil, nil))
+			r.recordOwnedServiceAccount(&serviceAccountToCreate.ObjectMeta)
+			continue
+		}
+
 		slog.Info("Creating Service Account", "name", serviceAccountToCreate.Name)
-		_, err := r.Clientset.CoreV1().ServiceAccounts(serviceAccountToCreate.ObjectMeta.Namespace).Create(context.TODO(), &serviceAccountToCreate, metav1.CreateOptions{})
+		err := r.applyServiceAccount(&serviceAccountToCreate)
 		if err != nil {
 			slog.Error("Error creating Service Account", "name", serviceAccountToCreate.Name, "error", err)
-			metrics.ErrorCounter.Inc()
+			r.recordFailure("ServiceAccount", &serviceAccountToCreate.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 		} else {
+			r.managed.ServiceAccounts++
+			r.recordOwnedServiceAccount(&serviceAccountToCreate.ObjectMeta)
 			metrics.ChangeCounter.WithLabelValues("serviceaccounts", "create").Inc()
 		}
 	}
@@ -224,15 +378,299 @@ func (r *Reconciler) reconcileServiceAccounts(requested *[]v1.ServiceAccount) er
 	return nil
 }
 
-func (r *Reconciler) reconcileClusterRoleBindings(requested *[]rbacv1.ClusterRoleBinding) error {
-	existing, err := r.Clientset.RbacV1().ClusterRoleBindings().List(context.TODO(), kube.ListOptions)
+// reconcileSecrets creates the token Secrets of managed Service Accounts. The
+//
+//	type of a Secret can not be changed, so Secrets that drifted are replaced.
+//	The token controller fills in a token for any Service Account a Secret
+//	names, so Secrets are only created for Service Accounts this RBAC
+//	Definition owns.
+func (r *Reconciler) reconcileSecrets(p *Parser) error {
+	requested := &p.parsedSecrets
+
+	existing, err := r.listSecrets()
+	if err != nil {
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		return err
+	}
+
+	matchingSecrets := []v1.Secret{}
+	secretsToCreate := []v1.Secret{}
+
+	for _, requestedSecret := range *requested {
+		alreadyExists := false
+		for _, existingSecret := range existing.Items {
+			if secretMatches(&existingSecret, &requestedSecret) {
+				alreadyExists = true
+				matchingSecrets = append(matchingSecrets, existingSecret)
+				r.managed.Secrets++
+				break
+			}
+		}
+
+		if alreadyExists {
+			slog.Debug("Secret already exists", "name", requestedSecret.Name, "namespace", requestedSecret.Namespace)
+		} else {
+			secretsToCreate = append(secretsToCreate, requestedSecret)
+		}
+	}
+
+	for _, existingSecret := range existing.Items {
+		if !reflect.DeepEqual(existingSecret.OwnerReferences, r.ownerRefs) {
+			continue
+		}
+
+		matchingRequest := false
+		for _, matchingSecret := range matchingSecrets {
+			if secretMatches(&existingSecret, &matchingSecret) {
+				matchingRequest = true
+				break
+			}
+		}
+		if matchingRequest {
+			continue
+		}
+
+		if p.retains("Secret", &existingSecret.ObjectMeta) {
+			slog.Info("Keeping Secret from invalid RBAC Binding", "name", existingSecret.Name, "namespace", existingSecret.Namespace)
+			continue
+		}
+
+		if r.plan != nil {
+			r.plan.Delete = append(r.plan.Delete, plannedChange("Secret", &existingSecret.ObjectMeta, nil, nil))
+			continue
+		}
+
+		slog.Info("Deleting Secret", "name", existingSecret.Name, "namespace", existingSecret.Namespace)
+		err := r.Clientset.CoreV1().Secrets(existingSecret.Namespace).Delete(context.TODO(), existingSecret.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			slog.Error("Error deleting Secret", "name", existingSecret.Name, "error", err)
+			r.recordFailure("Secret", &existingSecret.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			metrics.ChangeCounter.WithLabelValues("secrets", "delete").Inc()
+		}
+	}
+
+	for _, secretToCreate := range secretsToCreate {
+		saMeta := metav1.ObjectMeta{Namespace: secretToCreate.Namespace, Name: secretToCreate.Annotations[v1.ServiceAccountNameKey]}
+		if !r.ownedServiceAccounts.Has(objectKey("ServiceAccount", &saMeta)) {
+			slog.Error("Skipping Secret of a Service Account that is not managed", "name", secretToCreate.Name, "serviceAccount", saMeta.Name)
+			r.recordFailure("Secret", &secretToCreate.ObjectMeta, fmt.Errorf("Service Account %q is not managed by this RBAC Definition, its token Secret was not created", saMeta.Name))
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+			continue
+		}
+
+		if r.plan != nil {
+			r.plan.Create = append(r.plan.Create, plannedChange("Secret", &secretToCreate.ObjectMeta, nil, nil))
+			continue
+		}
+
+		slog.Info("Creating Secret", "name", secretToCreate.Name, "namespace", secretToCreate.Namespace)
+		err := r.applySecret(&secretToCreate)
+		if err != nil {
+			slog.Error("Error creating Secret", "name", secretToCreate.Name, "error", err)
+			r.recordFailure("Secret", &secretToCreate.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			r.managed.Secrets++
+			metrics.ChangeCounter.WithLabelValues("secrets", "create").Inc()
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) reconcileClusterRoles(p *Parser) error {
+	requested := &p.parsedClusterRoles
+
+	existing, err := r.listClusterRoles()
 	if err != nil {
-		metrics.ErrorCounter.Inc()
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		return err
+	}
+
+	clusterRolesToApply := []rbacv1.ClusterRole{}
+	requestedNames := map[string]bool{}
+
+	for _, requestedCR := range *requested {
+		requestedNames[requestedCR.Name] = true
+
+		alreadyExists := false
+		for _, existingCR := range existing.Items {
+			if crMatches(&existingCR, &requestedCR) {
+				alreadyExists = true
+				r.managed.ClusterRoles++
+				break
+			}
+		}
+
+		if alreadyExists {
+			slog.Debug("Cluster Role already exists", "name", requestedCR.Name)
+		} else {
+			clusterRolesToApply = append(clusterRolesToApply, requestedCR)
+		}
+	}
+
+	for _, existingCR := range existing.Items {
+		if !reflect.DeepEqual(existingCR.OwnerReferences, r.ownerRefs) || requestedNames[existingCR.Name] {
+			continue
+		}
+
+		if r.plan != nil {
+			r.plan.Delete = append(r.plan.Delete, plannedChange("ClusterRole", &existingCR.ObjectMeta, nil, nil))
+			continue
+		}
+
+		slog.Info("Deleting Cluster Role", "name", existingCR.Name)
+		err := r.Clientset.RbacV1().ClusterRoles().Delete(context.TODO(), existingCR.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			slog.Error("Error deleting Cluster Role", "name", existingCR.Name, "error", err)
+			r.recordFailure("ClusterRole", &existingCR.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			metrics.ChangeCounter.WithLabelValues("clusterroles", "delete").Inc()
+		}
+	}
+
+	for _, clusterRoleToApply := range clusterRolesToApply {
+		// Cluster Roles are mutable, so an existing one is updated in place
+		action := "create"
+		for _, existingCR := range existing.Items {
+			if metaMatches(&existingCR.ObjectMeta, &clusterRoleToApply.ObjectMeta) {
+				action = "update"
+				break
+			}
+		}
+
+		if r.plan != nil {
+			change := plannedChange("ClusterRole", &clusterRoleToApply.ObjectMeta, nil, nil)
+			if action == "update" {
+				r.plan.Update = append(r.plan.Update, change)
+			} else {
+				r.plan.Create = append(r.plan.Create, change)
+			}
+			continue
+		}
+
+		slog.Info("Applying Cluster Role", "name", clusterRoleToApply.Name, "action", action)
+		err := r.applyClusterRole(&clusterRoleToApply)
+		if err != nil {
+			slog.Error("Error applying Cluster Role", "name", clusterRoleToApply.Name, "error", err)
+			r.recordFailure("ClusterRole", &clusterRoleToApply.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			r.managed.ClusterRoles++
+			metrics.ChangeCounter.WithLabelValues("clusterroles", action).Inc()
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) reconcileRoles(p *Parser) error {
+	requested := &p.parsedRoles
+
+	existing, err := r.listRoles()
+	if err != nil {
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		return err
+	}
+
+	rolesToApply := []rbacv1.Role{}
+	requestedKeys := map[string]bool{}
+
+	for _, requestedRole := range *requested {
+		requestedKeys[objectKey("Role", &requestedRole.ObjectMeta)] = true
+
+		alreadyExists := false
+		for _, existingRole := range existing.Items {
+			if roleMatches(&existingRole, &requestedRole) {
+				alreadyExists = true
+				r.managed.Roles++
+				break
+			}
+		}
+
+		if alreadyExists {
+			slog.Debug("Role already exists", "name", requestedRole.Name, "namespace", requestedRole.Namespace)
+		} else {
+			rolesToApply = append(rolesToApply, requestedRole)
+		}
+	}
+
+	for _, existingRole := range existing.Items {
+		if !reflect.DeepEqual(existingRole.OwnerReferences, r.ownerRefs) || requestedKeys[objectKey("Role", &existingRole.ObjectMeta)] {
+			continue
+		}
+
+		if p.retains("Role", &existingRole.ObjectMeta) {
+			slog.Info("Keeping Role from invalid RBAC Binding", "name", existingRole.Name, "namespace", existingRole.Namespace)
+			continue
+		}
+
+		if r.plan != nil {
+			r.plan.Delete = append(r.plan.Delete, plannedChange("Role", &existingRole.ObjectMeta, nil, nil))
+			continue
+		}
+
+		slog.Info("Deleting Role", "name", existingRole.Name, "namespace", existingRole.Namespace)
+		err := r.Clientset.RbacV1().Roles(existingRole.Namespace).Delete(context.TODO(), existingRole.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			slog.Error("Error deleting Role", "name", existingRole.Name, "error", err)
+			r.recordFailure("Role", &existingRole.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			metrics.ChangeCounter.WithLabelValues("roles", "delete").Inc()
+		}
+	}
+
+	for _, roleToApply := range rolesToApply {
+		// Roles are mutable, so an existing one is updated in place
+		action := "create"
+		for _, existingRole := range existing.Items {
+			if metaMatches(&existingRole.ObjectMeta, &roleToApply.ObjectMeta) {
+				action = "update"
+				break
+			}
+		}
+
+		if r.plan != nil {
+			change := plannedChange("Role", &roleToApply.ObjectMeta, nil, nil)
+			if action == "update" {
+				r.plan.Update = append(r.plan.Update, change)
+			} else {
+				r.plan.Create = append(r.plan.Create, change)
+			}
+			continue
+		}
+
+		slog.Info("Applying Role", "name", roleToApply.Name, "namespace", roleToApply.Namespace, "action", action)
+		err := r.applyRole(&roleToApply)
+		if err != nil {
+			slog.Error("Error applying Role", "name", roleToApply.Name, "error", err)
+			r.recordFailure("Role", &roleToApply.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			r.managed.Roles++
+			metrics.ChangeCounter.WithLabelValues("roles", action).Inc()
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) reconcileClusterRoleBindings(p *Parser) error {
+	requested := &p.parsedClusterRoleBindings
+
+	existing, err := r.listClusterRoleBindings()
+	if err != nil {
+		metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 		return err
 	}
 
 	matchingClusterRoleBindings := []rbacv1.ClusterRoleBinding{}
 	clusterRoleBindingsToCreate := []rbacv1.ClusterRoleBinding{}
+	clusterRoleBindingsToUpdate := []rbacv1.ClusterRoleBinding{}
 
 	for _, requestedCRB := range *requested {
 		alreadyExists := false
@@ -240,14 +678,27 @@ func (r *Reconciler) reconcileClusterRoleBindings(requested *[]rbacv1.ClusterRol
 			if crbMatches(&existingCRB, &requestedCRB) {
 				alreadyExists = true
 				matchingClusterRoleBindings = append(matchingClusterRoleBindings, existingCRB)
+				r.managed.ClusterRoleBindings++
 				break
 			}
 		}
 
-		if !alreadyExists {
-			clusterRoleBindingsToCreate = append(clusterRoleBindingsToCreate, requestedCRB)
-		} else {
+		if alreadyExists {
 			slog.Debug("Cluster Role Binding already exists", "name", requestedCRB.Name)
+			continue
+		}
+
+		updatable := false
+		for _, existingCRB := range existing.Items {
+			if crbUpdatable(&existingCRB, &requestedCRB) {
+				updatable = true
+				clusterRoleBindingsToUpdate = append(clusterRoleBindingsToUpdate, requestedCRB)
+				break
+			}
+		}
+
+		if !updatable {
+			clusterRoleBindingsToCreate = append(clusterRoleBindingsToCreate, requestedCRB)
 		}
 	}
 
@@ -260,13 +711,30 @@ func (r *Reconciler) reconcileClusterRoleBindings(requested *[]rbacv1.ClusterRol
 					break
 				}
 			}
+			for _, updatedCRB := range clusterRoleBindingsToUpdate {
+				if metaMatches(&existingCRB.ObjectMeta, &updatedCRB.ObjectMeta) {
+					matchingRequest = true
+					break
+				}
+			}
 
 			if !matchingRequest {
+				if p.retains("ClusterRoleBinding", &existingCRB.ObjectMeta) {
+					slog.Info("Retaining Cluster Role Binding of invalid RBAC Binding", "name", existingCRB.Name)
+					continue
+				}
+
+				if r.plan != nil {
+					r.plan.Delete = append(r.plan.Delete, plannedChange("ClusterRoleBinding", &existingCRB.ObjectMeta, &existingCRB.RoleRef, existingCRB.Subjects))
+					continue
+				}
+
 				slog.Info("Deleting Cluster Role Binding", "name", existingCRB.Name)
 				err := r.Clientset.RbacV1().ClusterRoleBindings().Delete(context.TODO(), existingCRB.Name, metav1.DeleteOptions{})
-				if err != nil {
+				if err != nil && !apierrors.IsNotFound(err) {
 					slog.Error("Error deleting Cluster Role Binding", "name", existingCRB.Name, "error", err)
-					metrics.ErrorCounter.Inc()
+					r.recordFailure("ClusterRoleBinding", &existingCRB.ObjectMeta, err)
+					metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 				} else {
 					metrics.ChangeCounter.WithLabelValues("clusterrolebindings", "delete").Inc()
 				}
@@ -276,13 +744,50 @@ func (r *Reconciler) reconcileClusterRoleBindings(requested *[]rbacv1.ClusterRol
 		}
 	}
 
+	for _, clusterRoleBindingToUpdate := range clusterRoleBindingsToUpdate {
+		if p.requiresApproval(&clusterRoleBindingToUpdate.RoleRef) {
+			slog.Info("Cluster Role Binding requires approval", "name", clusterRoleBindingToUpdate.Name)
+			r.recordPendingApproval("ClusterRoleBinding", &clusterRoleBindingToUpdate.ObjectMeta, &clusterRoleBindingToUpdate.RoleRef, clusterRoleBindingToUpdate.Subjects)
+			continue
+		}
+
+		if r.plan != nil {
+			r.plan.Update = append(r.plan.Update, plannedChange("ClusterRoleBinding", &clusterRoleBindingToUpdate.ObjectMeta, &clusterRoleBindingToUpdate.RoleRef, clusterRoleBindingToUpdate.Subjects))
+			continue
+		}
+
+		slog.Info("Updating Cluster Role Binding", "name", clusterRoleBindingToUpdate.Name)
+		err := r.applyClusterRoleBinding(&clusterRoleBindingToUpdate)
+		if err != nil {
+			slog.Error("Error updating Cluster Role Binding", "name", clusterRoleBindingToUpdate.Name, "error", err)
+			r.recordFailure("ClusterRoleBinding", &clusterRoleBindingToUpdate.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			r.managed.ClusterRoleBindings++
+			metrics.ChangeCounter.WithLabelValues("clusterrolebindings", "update").Inc()
+		}
+	}
+
 	for _, clusterRoleBindingToCreate := range clusterRoleBindingsToCreate {
+		if p.requiresApproval(&clusterRoleBindingToCreate.RoleRef) {
+			slog.Info("Cluster Role Binding requires approval", "name", clusterRoleBindingToCreate.Name)
+			r.recordPendingApproval("ClusterRoleBinding", &clusterRoleBindingToCreate.ObjectMeta, &clusterRoleBindingToCreate.RoleRef, clusterRoleBindingToCreate.Subjects)
+			continue
+		}
+
+		if r.plan != nil {
+			r.plan.Create = append(r.plan.Create, plannedChange("ClusterRoleBinding", &clusterRoleBindingToCreate.ObjectMeta, &clusterRoleBindingToCreate.RoleRef, clusterRoleBindingToCreate.Subjects))
+			continue
+		}
+
 		slog.Info("Creating Cluster Role Binding", "name", clusterRoleBindingToCreate.Name)
-		_, err := r.Clientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), &clusterRoleBindingToCreate, metav1.CreateOptions{})
+		err := r.applyClusterRoleBinding(&clusterRoleBindingToCreate)
 		if err != nil {
 			slog.Error("Error creating Cluster Role Binding", "name", clusterRoleBindingToCreate.Name, "error", err)
-			metrics.ErrorCounter.Inc()
+			r.recordFailure("ClusterRoleBinding", &clusterRoleBindingToCreate.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 		} else {
+			r.managed.ClusterRoleBindings++
 			metrics.ChangeCounter.WithLabelValues("clusterrolebindings", "create").Inc()
 		}
 	}
@@ -290,14 +795,17 @@ func (r *Reconciler) reconcileClusterRoleBindings(requested *[]rbacv1.ClusterRol
 	return nil
 }
 
-func (r *Reconciler) reconcileRoleBindings(requested *[]rbacv1.RoleBinding) error {
-	existing, err := r.Clientset.RbacV1().RoleBindings("").List(context.TODO(), kube.ListOptions)
+func (r *Reconciler) reconcileRoleBindings(p *Parser) error {
+	requested := &p.parsedRoleBindings
+
+	existing, err := r.listRoleBindings()
 	if err != nil {
 		return err
 	}
 
 	matchingRoleBindings := []rbacv1.RoleBinding{}
 	roleBindingsToCreate := []rbacv1.RoleBinding{}
+	roleBindingsToUpdate := []rbacv1.RoleBinding{}
 
 	for _, requestedRB := range *requested {
 		alreadyExists := false
@@ -305,14 +813,27 @@ func (r *Reconciler) reconcileRoleBindings(requested *[]rbacv1.RoleBinding) erro
 			if rbMatches(&existingRB, &requestedRB) {
 				alreadyExists = true
 				matchingRoleBindings = append(matchingRoleBindings, existingRB)
+				r.managed.RoleBindings++
 				break
 			}
 		}
 
-		if !alreadyExists {
-			roleBindingsToCreate = append(roleBindingsToCreate, requestedRB)
-		} else {
+		if alreadyExists {
 			slog.Debug("Role Binding already exists", "name", requestedRB.Name)
+			continue
+		}
+
+		updatable := false
+		for _, existingRB := range existing.Items {
+			if rbUpdatable(&existingRB, &requestedRB) {
+				updatable = true
+				roleBindingsToUpdate = append(roleBindingsToUpdate, requestedRB)
+				break
+			}
+		}
+
+		if !updatable {
+			roleBindingsToCreate = append(roleBindingsToCreate, requestedRB)
 		}
 	}
 
@@ -325,13 +846,30 @@ func (r *Reconciler) reconcileRoleBindings(requested *[]rbacv1.RoleBinding) erro
 					break
 				}
 			}
+			for _, updatedRB := range roleBindingsToUpdate {
+				if metaMatches(&existingRB.ObjectMeta, &updatedRB.ObjectMeta) {
+					matchingRequest = true
+					break
+				}
+			}
 
 			if !matchingRequest {
+				if p.retains("RoleBinding", &existingRB.ObjectMeta) {
+					slog.Info("Retaining Role Binding of invalid RBAC Binding", "name", existingRB.Name)
+					continue
+				}
+
+				if r.plan != nil {
+					r.plan.Delete = append(r.plan.Delete, plannedChange("RoleBinding", &existingRB.ObjectMeta, &existingRB.RoleRef, existingRB.Subjects))
+					continue
+				}
+
 				slog.Info("Deleting Role Binding", "name", existingRB.Name)
 				err := r.Clientset.RbacV1().RoleBindings(existingRB.Namespace).Delete(context.TODO(), existingRB.Name, metav1.DeleteOptions{})
-				if err != nil {
+				if err != nil && !apierrors.IsNotFound(err) {
 					slog.Info("Error deleting Role Binding", "name", existingRB.Name, "error", err)
-					metrics.ErrorCounter.Inc()
+					r.recordFailure("RoleBinding", &existingRB.ObjectMeta, err)
+					metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 				} else {
 					metrics.ChangeCounter.WithLabelValues("rolebindings", "delete").Inc()
 				}
@@ -341,13 +879,50 @@ func (r *Reconciler) reconcileRoleBindings(requested *[]rbacv1.RoleBinding) erro
 		}
 	}
 
+	for _, roleBindingToUpdate := range roleBindingsToUpdate {
+		if p.requiresApproval(&roleBindingToUpdate.RoleRef) {
+			slog.Info("Role Binding requires approval", "name", roleBindingToUpdate.Name)
+			r.recordPendingApproval("RoleBinding", &roleBindingToUpdate.ObjectMeta, &roleBindingToUpdate.RoleRef, roleBindingToUpdate.Subjects)
+			continue
+		}
+
+		if r.plan != nil {
+			r.plan.Update = append(r.plan.Update, plannedChange("RoleBinding", &roleBindingToUpdate.ObjectMeta, &roleBindingToUpdate.RoleRef, roleBindingToUpdate.Subjects))
+			continue
+		}
+
+		slog.Info("Updating Role Binding", "name", roleBindingToUpdate.Name)
+		err := r.applyRoleBinding(&roleBindingToUpdate)
+		if err != nil {
+			slog.Error("Error updating Role Binding", "name", roleBindingToUpdate.Name, "error", err)
+			r.recordFailure("RoleBinding", &roleBindingToUpdate.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
+		} else {
+			r.managed.RoleBindings++
+			metrics.ChangeCounter.WithLabelValues("rolebindings", "update").Inc()
+		}
+	}
+
 	for _, roleBindingToCreate := range roleBindingsToCreate {
+		if p.requiresApproval(&roleBindingToCreate.RoleRef) {
+			slog.Info("Role Binding requires approval", "name", roleBindingToCreate.Name)
+			r.recordPendingApproval("RoleBinding", &roleBindingToCreate.ObjectMeta, &roleBindingToCreate.RoleRef, roleBindingToCreate.Subjects)
+			continue
+		}
+
+		if r.plan != nil {
+			r.plan.Create = append(r.plan.Create, plannedChange("RoleBinding", &roleBindingToCreate.ObjectMeta, &roleBindingToCreate.RoleRef, roleBindingToCreate.Subjects))
+			continue
+		}
+
 		slog.Info("Creating Role Binding", "name", roleBindingToCreate.Name)
-		_, err := r.Clientset.RbacV1().RoleBindings(roleBindingToCreate.ObjectMeta.Namespace).Create(context.TODO(), &roleBindingToCreate, metav1.CreateOptions{})
+		err := r.applyRoleBinding(&roleBindingToCreate)
 		if err != nil {
 			slog.Error("Error creating Role Binding", "name", roleBindingToCreate.Name, "error", err)
-			metrics.ErrorCounter.Inc()
+			r.recordFailure("RoleBinding", &roleBindingToCreate.ObjectMeta, err)
+			metrics.ErrorCounter.WithLabelValues(metrics.ErrorReconcile).Inc()
 		} else {
+			r.managed.RoleBindings++
 			metrics.ChangeCounter.WithLabelValues("rolebindings", "create").Inc()
 		}
 	}
@@ -364,3 +939,13 @@ func rbacDefOwnerRefs(rbacDef *rbacmanagerv1beta1.RBACDefinition) []metav1.Owner
 		}),
 	}
 }
+
+func namespacedRbacDefOwnerRefs(nrd *rbacmanagerv1beta1.NamespacedRBACDefinition) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		*metav1.NewControllerRef(nrd, schema.GroupVersionKind{
+			Group:   rbacmanagerv1beta1.SchemeGroupVersion.Group,
+			Version: rbacmanagerv1beta1.SchemeGroupVersion.Version,
+			Kind:    "NamespacedRBACDefinition",
+		}),
+	}
+}
@@ -16,27 +16,37 @@ package reconciler
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	rbacv1ac "k8s.io/client-go/applyconfigurations/rbac/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
 	"github.com/fairwindsops/rbac-manager/pkg/kube"
+	"github.com/fairwindsops/rbac-manager/pkg/metrics"
 )
 
 func TestReconcileRbacDefEmpty(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDef.Name = "empty-example"
 	testEmptyExample(t, client, rbacDef.Name)
 }
 
 func TestReconcileRbacDefChanges(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDef.Name = "changing-example"
 	testEmptyExample(t, client, rbacDef.Name)
@@ -108,7 +118,7 @@ func TestReconcileRbacDefChanges(t *testing.T) {
 }
 
 func TestReconcileRbacDefServiceAccounts(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDef.Name = "service-account-example"
 	testEmptyExample(t, client, rbacDef.Name)
@@ -171,7 +181,7 @@ func TestReconcileRbacDefServiceAccounts(t *testing.T) {
 }
 
 func TestReconcileRbacDefInvalid(t *testing.T) {
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDef.Name = "invalid-example"
 	testEmptyExample(t, client, rbacDef.Name)
@@ -195,10 +205,1576 @@ func TestReconcileRbacDefInvalid(t *testing.T) {
 	newReconcileTest(t, client, rbacDef, []rbacv1.RoleBinding{}, []rbacv1.ClusterRoleBinding{}, []corev1.ServiceAccount{})
 }
 
+func TestReconcileRbacDefStatus(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "status-example"
+	rbacDef.Generation = 2
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "ci-bot",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "ci-bot",
+				Namespace: "bots",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Namespace:   "web",
+			ClusterRole: "edit",
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 2, rbacDef.Status.ObservedGeneration)
+	assert.NotNil(t, rbacDef.Status.LastReconcileTime)
+	assert.Equal(t, rbacmanagerv1beta1.ManagedObjectCounts{
+		ClusterRoleBindings: 1,
+		RoleBindings:        1,
+		ServiceAccounts:     1,
+	}, rbacDef.Status.Managed)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady))
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionInvalidSpec))
+	assert.Len(t, rbacDef.Status.RBACBindings, 1)
+	assert.Equal(t, "ci-bot", rbacDef.Status.RBACBindings[0].Name)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionReady))
+
+	// second binding is missing a namespace
+	rbacDef.Generation = 3
+	rbacDef.RBACBindings = append(rbacDef.RBACBindings, rbacmanagerv1beta1.RBACBinding{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: "joe",
+			},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "view",
+		}},
+	})
+
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 3, rbacDef.Status.ObservedGeneration)
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady))
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionInvalidSpec))
+	assert.Len(t, rbacDef.Status.RBACBindings, 2)
+	assert.False(t, meta.IsStatusConditionTrue(rbacDef.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionInvalidSpec))
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.RBACBindings[1].Conditions, rbacmanagerv1beta1.ConditionInvalidSpec))
+}
+
+func TestReconcileRbacDefUpdatesInPlace(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "update-example"
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: "jan",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Namespace:   "web",
+			ClusterRole: "edit",
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	rbacDef.RBACBindings[0].Subjects = append(rbacDef.RBACBindings[0].Subjects, rbacmanagerv1beta1.Subject{
+		Subject: rbacv1.Subject{
+			Kind: rbacv1.UserKind,
+			Name: "joe",
+		},
+	})
+	expectedSubjects := []rbacv1.Subject{{
+		Kind: rbacv1.UserKind,
+		Name: "jan",
+	}, {
+		Kind: rbacv1.UserKind,
+		Name: "joe",
+	}}
+
+	plan, err := r.Plan(&rbacDef)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Create)
+	assert.Empty(t, plan.Delete)
+	assert.ElementsMatch(t, []rbacmanagerv1beta1.PlannedChange{{
+		Kind:     "ClusterRoleBinding",
+		Name:     "update-example-devs-view",
+		RoleRef:  &rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		Subjects: expectedSubjects,
+	}, {
+		Kind:      "RoleBinding",
+		Name:      "update-example-devs-edit",
+		Namespace: "web",
+		RoleRef:   &rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		Subjects:  expectedSubjects,
+	}}, plan.Update)
+
+	// a subject change must not delete and recreate the bindings
+	client.ClearActions()
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "rolebindings" || action.GetResource().Resource == "clusterrolebindings" {
+			assert.NotContains(t, []string{"create", "delete"}, action.GetVerb())
+		}
+	}
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "update-example-devs-view",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "view",
+		},
+		Subjects: expectedSubjects,
+	}})
+	expectRoleBindings(t, client, []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "update-example-devs-edit",
+			Namespace: "web",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "edit",
+		},
+		Subjects: expectedSubjects,
+	}})
+	assert.Equal(t, 1, rbacDef.Status.Managed.ClusterRoleBindings)
+	assert.Equal(t, 1, rbacDef.Status.Managed.RoleBindings)
+
+	// a role ref change still requires the binding to be recreated
+	rbacDef.RBACBindings[0].RoleBindings[0].ClusterRole = "admin"
+	newReconcileTest(t, client, rbacDef, []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "update-example-devs-admin",
+			Namespace: "web",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "admin",
+		},
+		Subjects: expectedSubjects,
+	}}, []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "update-example-devs-view",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "view",
+		},
+		Subjects: expectedSubjects,
+	}}, []corev1.ServiceAccount{})
+}
+
+func TestReconcileRbacDefApply(t *testing.T) {
+	client := fake.NewClientset(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "apply-example-devs-admin",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "admin",
+		},
+	})
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "apply-example"
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: "jan",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "admin",
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Namespace:   "web",
+			ClusterRole: "edit",
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	// an existing binding that rbac-manager does not own is left alone
+	crb, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), "apply-example-devs-admin", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, crb.Subjects)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+	assert.Contains(t, meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded).Message, "already exists")
+
+	// fields set by other field managers are kept
+	_, err = client.RbacV1().RoleBindings("web").Apply(context.TODO(),
+		rbacv1ac.RoleBinding("apply-example-devs-edit", "web").WithLabels(map[string]string{"team": "devs"}),
+		metav1.ApplyOptions{FieldManager: "kubectl"})
+	assert.NoError(t, err)
+
+	rbacDef.RBACBindings[0].Subjects[0].Name = "joe"
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	rb, err := client.RbacV1().RoleBindings("web").Get(context.TODO(), "apply-example-devs-edit", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "devs", rb.Labels["team"])
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}}, rb.Subjects)
+
+	// conflicting changes from other field managers are reported, not overwritten
+	_, err = client.RbacV1().RoleBindings("web").Apply(context.TODO(),
+		rbacv1ac.RoleBinding("apply-example-devs-edit", "web").WithSubjects(rbacv1ac.Subject().WithKind(rbacv1.UserKind).WithName("eve")),
+		metav1.ApplyOptions{FieldManager: "kubectl", Force: true})
+	assert.NoError(t, err)
+
+	rbacDef.RBACBindings[0].Subjects[0].Name = "jan"
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	rb, err = client.RbacV1().RoleBindings("web").Get(context.TODO(), "apply-example-devs-edit", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "eve"}}, rb.Subjects)
+	assert.Contains(t, meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded).Message, "RoleBinding/web/apply-example-devs-edit")
+}
+
+func TestReconcileRbacDefApplyLegacyFields(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "legacy-example"
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: "joe",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+	}}
+
+	// bindings created before rbac-manager used server-side apply
+	_, err := client.RbacV1().ClusterRoleBindings().Create(context.TODO(), &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "legacy-example-devs-view",
+			Labels:          kube.Labels,
+			OwnerReferences: rbacDefOwnerRefs(&rbacDef),
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "view",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind: rbacv1.UserKind,
+			Name: "jan",
+		}},
+	}, metav1.CreateOptions{FieldManager: FieldManager})
+	assert.NoError(t, err)
+
+	r := Reconciler{Clientset: client}
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady))
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "legacy-example-devs-view",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "view",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind: rbacv1.UserKind,
+			Name: "joe",
+		}},
+	}})
+}
+
+func TestReconcileRbacDefPartialFailure(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "partial-example"
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "ci-bot",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "ci-bot",
+				Namespace: "bots",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+	}, {
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "dev-bot",
+				Namespace: "bots",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "edit",
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Namespace:   "web",
+			ClusterRole: "admin",
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	// a binding created before resources were annotated with their RBACBinding
+	_, err = client.RbacV1().ClusterRoleBindings().Apply(context.TODO(),
+		rbacv1ac.ClusterRoleBinding("partial-example-devs-edit").
+			WithLabels(kube.Labels).
+			WithOwnerReferences(ownerReferenceApplyConfigurations(rbacDefOwnerRefs(&rbacDef))...).
+			WithRoleRef(rbacv1ac.RoleRef().WithKind("ClusterRole").WithName("edit")).
+			WithSubjects(rbacv1ac.Subject().WithKind(rbacv1.ServiceAccountKind).WithName("dev-bot").WithNamespace("bots")),
+		applyOptions)
+	assert.NoError(t, err)
+
+	// a binding of another RBACBinding that only shares the name prefix
+	_, err = client.RbacV1().ClusterRoleBindings().Apply(context.TODO(),
+		rbacv1ac.ClusterRoleBinding("partial-example-devs-ops-view").
+			WithLabels(kube.Labels).
+			WithOwnerReferences(ownerReferenceApplyConfigurations(rbacDefOwnerRefs(&rbacDef))...).
+			WithRoleRef(rbacv1ac.RoleRef().WithKind("ClusterRole").WithName("view")),
+		applyOptions)
+	assert.NoError(t, err)
+
+	devsCrbs := []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "partial-example-devs-edit"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "dev-bot", Namespace: "bots"}},
+	}}
+	devsRbs := []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "partial-example-devs-admin", Namespace: "web"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "admin"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "dev-bot", Namespace: "bots"}},
+	}}
+
+	// an invalid binding keeps its resources while the rest are reconciled
+	rbacDef.RBACBindings[0].ClusterRoleBindings[0].ClusterRole = "cluster-admin"
+	rbacDef.RBACBindings[1].RoleBindings[0].Namespace = ""
+
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, append([]rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "partial-example-ci-bot-cluster-admin"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "ci-bot", Namespace: "bots"}},
+	}}, devsCrbs...))
+	expectRoleBindings(t, client, devsRbs)
+	expectServiceAccounts(t, client, []corev1.ServiceAccount{{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-bot", Namespace: "bots"},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "dev-bot", Namespace: "bots"},
+	}})
+
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionInvalidSpec))
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionReady))
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.RBACBindings[1].Conditions, rbacmanagerv1beta1.ConditionInvalidSpec))
+
+	// namespace changes skip the invalid binding without failing
+	err = r.ReconcileNamespaceChange(&rbacDef, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}})
+	assert.NoError(t, err)
+	expectRoleBindings(t, client, devsRbs)
+
+	// once the binding is valid again its resources are reconciled
+	rbacDef.RBACBindings[1].RoleBindings[0].Namespace = "web"
+
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, append([]rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "partial-example-ci-bot-cluster-admin"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "ci-bot", Namespace: "bots"}},
+	}}, devsCrbs[0]))
+	expectRoleBindings(t, client, devsRbs)
+}
+
+func TestReconcileRbacDefRoleNamespaceSelector(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "role-example"
+
+	createNamespace(t, client, "web", map[string]string{"team": "devs"})
+	createNamespace(t, client, "api", map[string]string{"team": "devs"})
+	createRole(t, client, "web", "deployer")
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: "joe",
+			},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Role:              "deployer",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "devs"}},
+		}},
+	}}
+
+	expectedRb := rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "role-example-devs-deployer-web",
+			Namespace: "web",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "Role",
+			Name: "deployer",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind: rbacv1.UserKind,
+			Name: "joe",
+		}},
+	}
+
+	newReconcileTest(t, client, rbacDef, []rbacv1.RoleBinding{expectedRb}, []rbacv1.ClusterRoleBinding{}, []corev1.ServiceAccount{})
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api"}, rbacDef.Status.RBACBindings[0].SkippedNamespaces)
+
+	// the namespace is bound once the Role exists
+	createRole(t, client, "api", "deployer")
+	apiRb := expectedRb
+	apiRb.Name = "role-example-devs-deployer-api"
+	apiRb.Namespace = "api"
+
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	assert.Empty(t, rbacDef.Status.RBACBindings[0].SkippedNamespaces)
+	expectRoleBindings(t, client, []rbacv1.RoleBinding{expectedRb, apiRb})
+}
+
+func TestReconcileNamespacedRbacDef(t *testing.T) {
+	client := fake.NewClientset()
+	nrd := rbacmanagerv1beta1.NamespacedRBACDefinition{}
+	nrd.Name = "tenant-example"
+	nrd.Namespace = "team-a"
+	nrd.UID = "tenant-example-uid"
+
+	nrd.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "deployers",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+		}, {
+			Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "deploy-bot"},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "edit",
+		}},
+	}, {
+		Name: "escalation",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "other-bot", Namespace: "team-b"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "cluster-admin",
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "admin",
+			Namespace:   "team-b",
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	r.NamespacedClusterRoles = sets.New("edit")
+	err := r.ReconcileNamespaced(&nrd)
+	assert.NoError(t, err)
+
+	// only resources in the namespace of the Namespaced RBAC Definition are created
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{})
+	expectRoleBindings(t, client, []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-example-deployers-edit", Namespace: "team-a"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		Subjects: []rbacv1.Subject{
+			{Kind: rbacv1.UserKind, Name: "joe"},
+			{Kind: rbacv1.ServiceAccountKind, Name: "deploy-bot", Namespace: "team-a"},
+		},
+	}})
+	expectServiceAccounts(t, client, []corev1.ServiceAccount{{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy-bot", Namespace: "team-a"},
+	}})
+
+	rb, err := client.RbacV1().RoleBindings("team-a").Get(context.TODO(), "tenant-example-deployers-edit", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "NamespacedRBACDefinition", rb.OwnerReferences[0].Kind)
+	assert.Equal(t, nrd.UID, rb.OwnerReferences[0].UID)
+
+	assert.True(t, meta.IsStatusConditionTrue(nrd.Status.Conditions, rbacmanagerv1beta1.ConditionInvalidSpec))
+	assert.Equal(t, 1, nrd.Status.Managed.RoleBindings)
+	assert.True(t, meta.IsStatusConditionTrue(nrd.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionReady))
+	assert.True(t, meta.IsStatusConditionTrue(nrd.Status.RBACBindings[1].Conditions, rbacmanagerv1beta1.ConditionInvalidSpec))
+
+	// removing a binding deletes its resources
+	nrd.RBACBindings = nrd.RBACBindings[1:]
+	err = r.ReconcileNamespaced(&nrd)
+	assert.NoError(t, err)
+
+	expectRoleBindings(t, client, []rbacv1.RoleBinding{})
+	expectServiceAccounts(t, client, []corev1.ServiceAccount{})
+}
+
+func TestReconcileNamespacedRbacDefAllowedClusterRoles(t *testing.T) {
+	client := fake.NewClientset()
+	nrd := rbacmanagerv1beta1.NamespacedRBACDefinition{}
+	nrd.Name = "tenant-example"
+	nrd.Namespace = "team-a"
+
+	nrd.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:     "admins",
+		Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "cluster-admin",
+		}, {
+			Role:         "secret-reader",
+			RoleTemplate: &rbacmanagerv1beta1.RoleTemplate{Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}}},
+		}, {
+			Role: "deployer",
+		}},
+	}}
+
+	// ClusterRoles that were allowed when the binding was created are
+	// removed once the operator stops allowing them
+	r := Reconciler{Clientset: client}
+	r.NamespacedClusterRoles = sets.New("cluster-admin")
+	err := r.ReconcileNamespaced(&nrd)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, nrd.Status.Managed.RoleBindings)
+
+	r.NamespacedClusterRoles = sets.New("edit")
+	err = r.ReconcileNamespaced(&nrd)
+	assert.NoError(t, err)
+
+	// only the Role that already exists in the namespace is bound
+	expectRoleBindings(t, client, []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-example-admins-deployer-team-a", Namespace: "team-a"},
+		RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "deployer"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}},
+	}})
+	roles, err := client.RbacV1().Roles("team-a").List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, roles.Items)
+
+	violation := meta.FindStatusCondition(nrd.Status.Conditions, rbacmanagerv1beta1.ConditionPolicyViolation)
+	if assert.NotNil(t, violation) {
+		assert.Equal(t, metav1.ConditionTrue, violation.Status)
+		assert.Contains(t, violation.Message, `ClusterRole "cluster-admin" is not allowed in a NamespacedRBACDefinition`)
+		assert.Contains(t, violation.Message, "role templates are not allowed in a NamespacedRBACDefinition")
+	}
+}
+
+func TestReconcileRbacDefSchedule(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "schedule-example"
+
+	inAnHour := metav1.NewTime(time.Now().Add(time.Hour))
+	inADay := metav1.NewTime(time.Now().Add(24 * time.Hour))
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "on-call",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+		}},
+		ExpiresAt: &inADay,
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "cluster-admin",
+		}},
+	}, {
+		Name: "next-shift",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "sue"},
+		}},
+		NotBefore: &inAnHour,
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "cluster-admin",
+		}},
+	}, {
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "dave"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "schedule-example-on-call-cluster-admin"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "schedule-example-devs-view"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "dave"}},
+	}})
+
+	assert.True(t, rbacDef.Status.NextScheduledChange.Equal(&inAnHour))
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady))
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionActive))
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.RBACBindings[1].Conditions, rbacmanagerv1beta1.ConditionActive))
+	assert.Nil(t, meta.FindStatusCondition(rbacDef.Status.RBACBindings[2].Conditions, rbacmanagerv1beta1.ConditionActive))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.InactiveBindingsGauge.WithLabelValues("", "schedule-example", reasonNotYetActive)))
+
+	// expired bindings are removed
+	anHourAgo := metav1.NewTime(time.Now().Add(-time.Hour))
+	rbacDef.RBACBindings[0].ExpiresAt = &anHourAgo
+
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "schedule-example-devs-view"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "dave"}},
+	}})
+
+	active := meta.FindStatusCondition(rbacDef.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionActive)
+	assert.Equal(t, metav1.ConditionFalse, active.Status)
+	assert.Equal(t, reasonExpired, active.Reason)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.InactiveBindingsGauge.WithLabelValues("", "schedule-example", reasonExpired)))
+}
+
+func TestReconcileRbacDefApproval(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "approval-example"
+	rbacDef.Annotations = map[string]string{RequestedByAnnotationKey: "joe"}
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "admins",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "cluster-admin",
+		}, {
+			ClusterRole: "view",
+		}},
+	}}
+
+	viewCrb := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "approval-example-admins-view"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}},
+	}
+	adminCrb := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "approval-example-admins-cluster-admin"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}},
+	}
+
+	// bindings to cluster-admin are held back until they are approved
+	r := Reconciler{Clientset: client}
+	r.ApprovalRequiredClusterRoles = sets.New("cluster-admin")
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{viewCrb})
+	assert.Len(t, rbacDef.Status.PendingApprovals, 1)
+	assert.Equal(t, adminCrb.Name, rbacDef.Status.PendingApprovals[0].Name)
+	ready := meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, reasonPending, ready.Reason)
+
+	// the requester can't approve their own change
+	rbacDef.Annotations[ApprovedByAnnotationKey] = "joe"
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{viewCrb})
+
+	rbacDef.Annotations[ApprovedByAnnotationKey] = "sue"
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{viewCrb, adminCrb})
+	assert.Empty(t, rbacDef.Status.PendingApprovals)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady))
+
+	// a new change keeps the approved binding as it is until it is approved again
+	rbacDef.Annotations = map[string]string{RequestedByAnnotationKey: "joe"}
+	rbacDef.RBACBindings[0].Subjects = append(rbacDef.RBACBindings[0].Subjects, rbacmanagerv1beta1.Subject{
+		Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "dave"},
+	})
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	viewCrb.Subjects = append(viewCrb.Subjects, rbacv1.Subject{Kind: rbacv1.UserKind, Name: "dave"})
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{viewCrb, adminCrb})
+	assert.Len(t, rbacDef.Status.PendingApprovals, 1)
+}
+
+func TestReconcileRbacDefPolicy(t *testing.T) {
+	policies := rbacmanagerv1beta1.RBACManagerPolicyList{}
+
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "policy-example"
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "admins",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "cluster-admin",
+		}},
+	}, {
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "dave"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+	}}
+
+	adminCrb := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-example-admins-cluster-admin"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}},
+	}
+	viewCrb := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy-example-devs-view"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "dave"}},
+	}
+
+	r := Reconciler{Clientset: client}
+	r.LoadPolicies = func() (rbacmanagerv1beta1.RBACManagerPolicyList, error) {
+		return policies, nil
+	}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{adminCrb, viewCrb})
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionPolicyViolation))
+
+	// bindings created before a policy forbids them are removed
+	policies.Items = []rbacmanagerv1beta1.RBACManagerPolicy{{
+		ObjectMeta:         metav1.ObjectMeta{Name: "baseline"},
+		DeniedClusterRoles: []string{"cluster-admin"},
+	}}
+	violations := testutil.ToFloat64(metrics.ErrorCounter.WithLabelValues(metrics.ErrorPolicyViolation))
+
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	expectClusterRoleBindings(t, client, []rbacv1.ClusterRoleBinding{viewCrb})
+	assert.Equal(t, violations+1, testutil.ToFloat64(metrics.ErrorCounter.WithLabelValues(metrics.ErrorPolicyViolation)))
+
+	violation := meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionPolicyViolation)
+	assert.Equal(t, metav1.ConditionTrue, violation.Status)
+	assert.Equal(t, `rbacBinding "admins": clusterRoleBindings[0]: ClusterRole "cluster-admin" is denied by policy "baseline"`, violation.Message)
+	ready := meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, reasonPolicyViolation, ready.Reason)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionPolicyViolation))
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionReady))
+	assert.Nil(t, meta.FindStatusCondition(rbacDef.Status.RBACBindings[1].Conditions, rbacmanagerv1beta1.ConditionPolicyViolation))
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.RBACBindings[1].Conditions, rbacmanagerv1beta1.ConditionReady))
+}
+
+func TestReconcileRbacDefRolesPolicy(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "roles-policy-example"
+
+	everything := []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}}
+	deploy := []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "update"}}}
+
+	rbacDef.ClusterRoles = []rbacmanagerv1beta1.ClusterRole{{
+		Name:   "not-cluster-admin",
+		Labels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-admin": "true"},
+		Rules:  everything,
+	}}
+	rbacDef.Roles = []rbacmanagerv1beta1.Role{{
+		Name:      "deployer",
+		Namespace: "kube-system",
+		Rules:     deploy,
+	}, {
+		Name:      "deployer",
+		Namespace: "web",
+		Rules:     deploy,
+	}}
+
+	r := Reconciler{Clientset: client}
+	r.LoadPolicies = func() (rbacmanagerv1beta1.RBACManagerPolicyList, error) {
+		return rbacmanagerv1beta1.RBACManagerPolicyList{Items: []rbacmanagerv1beta1.RBACManagerPolicy{{
+			ObjectMeta:          metav1.ObjectMeta{Name: "baseline"},
+			ForbiddenNamespaces: []string{"kube-system"},
+			DeniedRules:         []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+		}}}, nil
+	}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	// roles that would grant what a policy forbids are not created
+	_, err = client.RbacV1().ClusterRoles().Get(context.TODO(), "not-cluster-admin", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	_, err = client.RbacV1().Roles("kube-system").Get(context.TODO(), "deployer", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	_, err = client.RbacV1().Roles("web").Get(context.TODO(), "deployer", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	violation := meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionPolicyViolation)
+	assert.Equal(t, metav1.ConditionTrue, violation.Status)
+	assert.Equal(t, `ClusterRole "not-cluster-admin": rules grant deniedRules[0] of policy "baseline"; `+
+		`Role "kube-system/deployer": namespace "kube-system" is forbidden by policy "baseline"`, violation.Message)
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady))
+}
+
+func TestReconcileRbacDefRoles(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "roles-example"
+
+	readSecrets := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}}}
+	deploy := []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "update"}}}
+
+	rbacDef.ClusterRoles = []rbacmanagerv1beta1.ClusterRole{{
+		Name:   "secret-reader",
+		Labels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-view": "true"},
+		Rules:  readSecrets,
+	}}
+	rbacDef.Roles = []rbacmanagerv1beta1.Role{{
+		Name:      "deployer",
+		Namespace: "web",
+		Rules:     deploy,
+	}}
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Role:      "deployer",
+			Namespace: "web",
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	cr, err := client.RbacV1().ClusterRoles().Get(context.TODO(), "secret-reader", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, readSecrets, cr.Rules)
+	assert.Equal(t, "true", cr.Labels["rbac.authorization.k8s.io/aggregate-to-view"])
+	assert.Equal(t, kube.LabelValue, cr.Labels[kube.LabelKey])
+	assert.Equal(t, "RBACDefinition", cr.OwnerReferences[0].Kind)
+
+	role, err := client.RbacV1().Roles("web").Get(context.TODO(), "deployer", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, deploy, role.Rules)
+
+	assert.Equal(t, 1, rbacDef.Status.Managed.ClusterRoles)
+	assert.Equal(t, 1, rbacDef.Status.Managed.Roles)
+	assert.Equal(t, 1, rbacDef.Status.Managed.RoleBindings)
+
+	// rules are updated in place
+	rbacDef.ClusterRoles[0].Rules = deploy
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	cr, err = client.RbacV1().ClusterRoles().Get(context.TODO(), "secret-reader", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, deploy, cr.Rules)
+
+	// an invalid Role is reported without removing anything
+	rbacDef.Roles = append(rbacDef.Roles, rbacmanagerv1beta1.Role{Name: "deployer", Namespace: "web", Rules: deploy})
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	_, err = client.RbacV1().Roles("web").Get(context.TODO(), "deployer", metav1.GetOptions{})
+	assert.NoError(t, err)
+	invalid := meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionInvalidSpec)
+	assert.Equal(t, metav1.ConditionTrue, invalid.Status)
+	assert.Equal(t, `roles[1].name: Duplicate value: "deployer"`, invalid.Message)
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.RBACBindings[0].Conditions, rbacmanagerv1beta1.ConditionReady))
+	rbacDef.Roles = rbacDef.Roles[:1]
+
+	// roles removed from the RBAC Definition are deleted
+	rbacDef.ClusterRoles = nil
+	rbacDef.Roles = nil
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	crs, err := client.RbacV1().ClusterRoles().List(context.TODO(), kube.ListOptions)
+	assert.NoError(t, err)
+	assert.Empty(t, crs.Items)
+	roles, err := client.RbacV1().Roles("").List(context.TODO(), kube.ListOptions)
+	assert.NoError(t, err)
+	assert.Empty(t, roles.Items)
+
+	// existing roles owned by something else are not taken over
+	_, err = client.RbacV1().Roles("web").Create(context.TODO(), &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "web"},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	rbacDef.Roles = []rbacmanagerv1beta1.Role{{Name: "deployer", Namespace: "web", Rules: deploy}}
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	role, err = client.RbacV1().Roles("web").Get(context.TODO(), "deployer", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, role.Rules)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+}
+
+func TestReconcileRbacDefRoleTemplate(t *testing.T) {
+	client := fake.NewClientset()
+	for _, name := range []string{"web", "api", "db"} {
+		team := "dev"
+		if name == "db" {
+			team = "ops"
+		}
+		_, err := client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"team": team}},
+		}, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	deploy := []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "update"}}}
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "role-template-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Role:              "deployer",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "dev"}},
+			RoleTemplate:      &rbacmanagerv1beta1.RoleTemplate{Rules: deploy},
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	for _, namespace := range []string{"web", "api"} {
+		role, err := client.RbacV1().Roles(namespace).Get(context.TODO(), "deployer", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, deploy, role.Rules)
+		assert.Equal(t, "devs", role.Annotations[BindingAnnotationKey])
+
+		rb, err := client.RbacV1().RoleBindings(namespace).Get(context.TODO(), "role-template-example-devs-deployer-"+namespace, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, rbacv1.RoleRef{Kind: "Role", Name: "deployer"}, rb.RoleRef)
+	}
+	_, err = client.RbacV1().Roles("db").Get(context.TODO(), "deployer", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, 2, rbacDef.Status.Managed.Roles)
+	assert.Equal(t, 2, rbacDef.Status.Managed.RoleBindings)
+
+	// the Role and Role Binding are removed from namespaces that stop matching
+	api, err := client.CoreV1().Namespaces().Get(context.TODO(), "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	api.Labels["team"] = "ops"
+	_, err = client.CoreV1().Namespaces().Update(context.TODO(), api, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	err = r.ReconcileNamespaceChange(&rbacDef, api)
+	assert.NoError(t, err)
+
+	roles, err := client.RbacV1().Roles("").List(context.TODO(), kube.ListOptions)
+	assert.NoError(t, err)
+	assert.Len(t, roles.Items, 1)
+	assert.Equal(t, "web", roles.Items[0].Namespace)
+	rbs, err := client.RbacV1().RoleBindings("").List(context.TODO(), kube.ListOptions)
+	assert.NoError(t, err)
+	assert.Len(t, rbs.Items, 1)
+	assert.Equal(t, "web", rbs.Items[0].Namespace)
+
+	// template rules are updated in every namespace
+	rbacDef.RBACBindings[0].RoleBindings[0].RoleTemplate.Rules = []rbacv1.PolicyRule{
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+	}
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	role, err := client.RbacV1().Roles("web").Get(context.TODO(), "deployer", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"get"}, role.Rules[0].Verbs)
+}
+
+func TestReconcileRbacDefServiceAccountPerNamespace(t *testing.T) {
+	client := fake.NewClientset()
+	for _, name := range []string{"web", "api", "db"} {
+		team := "x"
+		if name == "db" {
+			team = "y"
+		}
+		_, err := client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"team": team}},
+		}, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "ci-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "ci",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject:          rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci-runner"},
+			ImagePullSecrets: []string{"registry"},
+		}, {
+			Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+		}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole:       "edit",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "x"}},
+		}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	for _, namespace := range []string{"web", "api"} {
+		sa, err := client.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), "ci-runner", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []corev1.LocalObjectReference{{Name: "registry"}}, sa.ImagePullSecrets)
+
+		rb, err := client.RbacV1().RoleBindings(namespace).Get(context.TODO(), "ci-example-ci-edit", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []rbacv1.Subject{
+			{Kind: rbacv1.ServiceAccountKind, Name: "ci-runner", Namespace: namespace},
+			{Kind: rbacv1.UserKind, Name: "joe"},
+		}, rb.Subjects)
+	}
+	_, err = client.CoreV1().ServiceAccounts("db").Get(context.TODO(), "ci-runner", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, 2, rbacDef.Status.Managed.ServiceAccounts)
+
+	// the Service Account is removed along with the Role Binding when a namespace stops matching
+	api, err := client.CoreV1().Namespaces().Get(context.TODO(), "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	api.Labels["team"] = "y"
+	_, err = client.CoreV1().Namespaces().Update(context.TODO(), api, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	err = r.ReconcileNamespaceChange(&rbacDef, api)
+	assert.NoError(t, err)
+
+	sas, err := client.CoreV1().ServiceAccounts("").List(context.TODO(), kube.ListOptions)
+	assert.NoError(t, err)
+	assert.Len(t, sas.Items, 1)
+	assert.Equal(t, "web", sas.Items[0].Namespace)
+}
+
+func TestReconcileRbacDefServiceAccountMetadata(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "uploader-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "uploader",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject:      rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "uploader", Namespace: "media"},
+			Labels:       map[string]string{"azure.workload.identity/use": "true"},
+			Annotations:  map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::111122223333:role/uploader"},
+			TokenSecrets: []string{"uploader-token"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	sa, err := client.CoreV1().ServiceAccounts("media").Get(context.TODO(), "uploader", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", sa.Labels["azure.workload.identity/use"])
+	assert.Equal(t, "arn:aws:iam::111122223333:role/uploader", sa.Annotations["eks.amazonaws.com/role-arn"])
+	assert.Equal(t, "eks.amazonaws.com/role-arn", sa.Annotations[ManagedAnnotationsAnnotationKey])
+
+	secret, err := client.CoreV1().Secrets("media").Get(context.TODO(), "uploader-token", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.SecretTypeServiceAccountToken, secret.Type)
+	assert.Equal(t, "uploader", secret.Annotations[corev1.ServiceAccountNameKey])
+	assert.Equal(t, 1, rbacDef.Status.Managed.ServiceAccounts)
+	assert.Equal(t, 1, rbacDef.Status.Managed.Secrets)
+
+	// conflicting changes are reported without recreating the Service Account
+	sa.Annotations["eks.amazonaws.com/role-arn"] = "arn:aws:iam::111122223333:role/other"
+	sa.Annotations["example.com/unmanaged"] = "kept"
+	_, err = client.CoreV1().ServiceAccounts("media").Update(context.TODO(), sa, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	err = client.CoreV1().Secrets("media").Delete(context.TODO(), "uploader-token", metav1.DeleteOptions{})
+	assert.NoError(t, err)
+
+	client.ClearActions()
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "serviceaccounts" {
+			assert.NotContains(t, []string{"create", "delete"}, action.GetVerb())
+		}
+	}
+
+	sa, err = client.CoreV1().ServiceAccounts("media").Get(context.TODO(), "uploader", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::111122223333:role/other", sa.Annotations["eks.amazonaws.com/role-arn"])
+	assert.Equal(t, "kept", sa.Annotations["example.com/unmanaged"])
+	assert.Contains(t, meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded).Message, "ServiceAccount/media/uploader")
+	_, err = client.CoreV1().Secrets("media").Get(context.TODO(), "uploader-token", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// annotations and token Secrets removed from the RBAC Definition are no longer
+	// managed, and annotations changed by hand are left to their new owner
+	rbacDef.RBACBindings[0].Subjects[0].Annotations = nil
+	rbacDef.RBACBindings[0].Subjects[0].TokenSecrets = nil
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	sa, err = client.CoreV1().ServiceAccounts("media").Get(context.TODO(), "uploader", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::111122223333:role/other", sa.Annotations["eks.amazonaws.com/role-arn"])
+	assert.Empty(t, sa.Annotations[ManagedAnnotationsAnnotationKey])
+	assert.Equal(t, "kept", sa.Annotations["example.com/unmanaged"])
+	assert.Equal(t, "true", sa.Labels["azure.workload.identity/use"])
+	_, err = client.CoreV1().Secrets("media").Get(context.TODO(), "uploader-token", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, 0, rbacDef.Status.Managed.Secrets)
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+}
+
+func TestReconcileRbacDefTokenSecretOfUnmanagedServiceAccount(t *testing.T) {
+	client := fake.NewClientset(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "deployer", Namespace: "kube-system"}})
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "token-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "deployer",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject:      rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "deployer", Namespace: "kube-system"},
+			TokenSecrets: []string{"deployer-token"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	// a token Secret would expose the token of a Service Account owned by someone else
+	_, err = client.CoreV1().Secrets("kube-system").Get(context.TODO(), "deployer-token", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, 0, rbacDef.Status.Managed.Secrets)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+	assert.Contains(t, meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded).Message, "Secret/kube-system/deployer-token")
+}
+
+func TestReconcileRbacDefServiceAccountDrift(t *testing.T) {
+	client := fake.NewClientset()
+	automount := false
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "drift-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "ci",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject:                      rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci-bot", Namespace: "bots"},
+			ImagePullSecrets:             []string{"registry", "mirror"},
+			AutomountServiceAccountToken: &automount,
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+	}}
+
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	// an automount flip by hand is reported as a conflict, not reverted
+	sa, err := client.CoreV1().ServiceAccounts("bots").Get(context.TODO(), "ci-bot", metav1.GetOptions{})
+	assert.NoError(t, err)
+	enabled := true
+	sa.AutomountServiceAccountToken = &enabled
+	_, err = client.CoreV1().ServiceAccounts("bots").Update(context.TODO(), sa, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	client.ClearActions()
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	sa, err = client.CoreV1().ServiceAccounts("bots").Get(context.TODO(), "ci-bot", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &enabled, sa.AutomountServiceAccountToken)
+	assert.Contains(t, meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded).Message, "ServiceAccount/bots/ci-bot")
+
+	// pull secrets and automount removed from the subject are removed in place,
+	// leaving the automount set by hand to its new owner
+	rbacDef.RBACBindings[0].Subjects[0].ImagePullSecrets = []string{"registry"}
+	rbacDef.RBACBindings[0].Subjects[0].AutomountServiceAccountToken = nil
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+
+	sa, err = client.CoreV1().ServiceAccounts("bots").Get(context.TODO(), "ci-bot", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &enabled, sa.AutomountServiceAccountToken)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "registry"}}, sa.ImagePullSecrets)
+	assert.Equal(t, 1, rbacDef.Status.Managed.ServiceAccounts)
+
+	// deleting a Service Account would invalidate the tokens of running pods
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "serviceaccounts" {
+			assert.NotContains(t, []string{"create", "delete"}, action.GetVerb())
+		}
+	}
+
+	// an automount that is not requested is not applied again and again
+	client.ClearActions()
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "serviceaccounts" {
+			assert.NotEqual(t, "patch", action.GetVerb())
+		}
+	}
+}
+
+func TestReconcileRbacDefAdopt(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "adopt-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "ci",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci-bot", Namespace: "bots"},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			Namespace:   "web",
+			ClusterRole: "edit",
+		}},
+	}}
+
+	// resources that were created before the RBAC Definition
+	_, err := client.CoreV1().ServiceAccounts("bots").Create(context.TODO(), &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci-bot", Namespace: "bots"},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	_, err = client.RbacV1().ClusterRoleBindings().Create(context.TODO(), &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "adopt-example-ci-view"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	controller := true
+	_, err = client.RbacV1().RoleBindings("web").Create(context.TODO(), &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "adopt-example-ci-edit",
+			Namespace: "web",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "example.com/v1",
+				Kind:       "Team",
+				Name:       "web",
+				Controller: &controller,
+			}},
+		},
+		RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "edit"},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	// existing resources are not adopted by default
+	r := Reconciler{Clientset: client}
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+	assert.Empty(t, rbacDef.Status.Adopted)
+	assert.Equal(t, 0, rbacDef.Status.Managed.ServiceAccounts)
+
+	rbacDef.Annotations = map[string]string{AdoptAnnotationKey: "true"}
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, []rbacmanagerv1beta1.PlannedChange{
+		{Kind: "ServiceAccount", Name: "ci-bot", Namespace: "bots"},
+		{Kind: "ClusterRoleBinding", Name: "adopt-example-ci-view"},
+	}, rbacDef.Status.Adopted)
+	assert.Equal(t, 1, rbacDef.Status.Managed.ServiceAccounts)
+	assert.Equal(t, 1, rbacDef.Status.Managed.ClusterRoleBindings)
+
+	sa, err := client.CoreV1().ServiceAccounts("bots").Get(context.TODO(), "ci-bot", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, kube.Labels, sa.Labels)
+	assert.Equal(t, rbacDefOwnerRefs(&rbacDef), sa.OwnerReferences)
+
+	crb, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), "adopt-example-ci-view", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, rbacDefOwnerRefs(&rbacDef), crb.OwnerReferences)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "ci-bot", Namespace: "bots"}}, crb.Subjects)
+
+	// a Role Binding owned by another controller is left alone
+	rb, err := client.RbacV1().RoleBindings("web").Get(context.TODO(), "adopt-example-ci-edit", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Team", rb.OwnerReferences[0].Kind)
+	assert.Empty(t, rb.Subjects)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+	assert.Contains(t, meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded).Message, "can not be adopted")
+
+	// adopted resources are managed like any other
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	assert.Empty(t, rbacDef.Status.Adopted)
+	assert.Equal(t, 1, rbacDef.Status.Managed.ServiceAccounts)
+}
+
+func TestReconcileRbacDefReader(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "reader-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:     "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			ClusterRole:       "edit",
+		}},
+	}}
+
+	// the cache knows about a Role Binding that has already been deleted
+	reader := crfake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "web"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "api"}},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "reader-example-devs-view",
+				Namespace:       "api",
+				Labels:          kube.Labels,
+				OwnerReferences: rbacDefOwnerRefs(&rbacDef),
+			},
+			RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+		},
+	).Build()
+
+	r := Reconciler{Clientset: client, Reader: reader}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	assert.False(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded))
+
+	deleted := false
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "list", action.GetVerb(), "unexpected list of %v", action.GetResource().Resource)
+		deleted = deleted || action.Matches("delete", "rolebindings")
+	}
+	assert.True(t, deleted, "expected the stale Role Binding to be deleted")
+
+	expectRoleBindings(t, client, []rbacv1.RoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{Name: "reader-example-devs-edit", Namespace: "web"},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}},
+	}})
+}
+
+func TestReconcileRbacDefReaderApply(t *testing.T) {
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "reader-apply-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:     "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "jan"}}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "view",
+		}},
+	}}
+
+	client := fake.NewClientset(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "reader-apply-example-devs-edit"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "edit"},
+	})
+	r := Reconciler{Clientset: client}
+	err := r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	// the cache holds the managed Cluster Role Binding, but not the unmanaged
+	// one with the same name as a newly requested binding
+	managed, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), "reader-apply-example-devs-view", metav1.GetOptions{})
+	assert.NoError(t, err)
+	r.Reader = crfake.NewClientBuilder().WithObjects(managed).Build()
+
+	rbacDef.RBACBindings[0].Subjects[0].Name = "joe"
+	rbacDef.RBACBindings[0].ClusterRoleBindings = append(rbacDef.RBACBindings[0].ClusterRoleBindings, rbacmanagerv1beta1.ClusterRoleBinding{ClusterRole: "edit"})
+	client.ClearActions()
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+
+	// only the resource missing from the cache is read from the API server
+	gets := []string{}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "get" {
+			gets = append(gets, action.(k8stesting.GetAction).GetName())
+		}
+	}
+	assert.Equal(t, []string{"reader-apply-example-devs-edit"}, gets)
+
+	crb, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), "reader-apply-example-devs-view", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "joe"}}, crb.Subjects)
+	assert.Contains(t, meta.FindStatusCondition(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionDegraded).Message, "reader-apply-example-devs-edit")
+}
+
+func TestPlanRbacDef(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "plan-example"
+
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "admins",
+		Subjects: []rbacmanagerv1beta1.Subject{{
+			Subject: rbacv1.Subject{
+				Kind: rbacv1.UserKind,
+				Name: "jan",
+			},
+		}},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+			ClusterRole: "admin",
+		}},
+	}}
+
+	expectedCrbs := []rbacv1.ClusterRoleBinding{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "plan-example-admins-admin",
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "ClusterRole",
+			Name: "admin",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind: rbacv1.UserKind,
+			Name: "jan",
+		}},
+	}}
+
+	newReconcileTest(t, client, rbacDef, []rbacv1.RoleBinding{}, expectedCrbs, []corev1.ServiceAccount{})
+
+	rbacDef.RBACBindings[0].ClusterRoleBindings[0].ClusterRole = "cluster-admin"
+	rbacDef.RBACBindings[0].RoleBindings = []rbacmanagerv1beta1.RoleBinding{{
+		Namespace:   "web",
+		ClusterRole: "edit",
+	}}
+
+	r := Reconciler{Clientset: client}
+	plan, err := r.Plan(&rbacDef)
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, []rbacmanagerv1beta1.PlannedChange{{
+		Kind:     "ClusterRoleBinding",
+		Name:     "plan-example-admins-cluster-admin",
+		RoleRef:  &rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects: []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "jan"}},
+	}, {
+		Kind:      "RoleBinding",
+		Name:      "plan-example-admins-edit",
+		Namespace: "web",
+		RoleRef:   &rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		Subjects:  []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "jan"}},
+	}}, plan.Create)
+	assert.ElementsMatch(t, []rbacmanagerv1beta1.PlannedChange{{
+		Kind:     "ClusterRoleBinding",
+		Name:     "plan-example-admins-admin",
+		RoleRef:  &rbacv1.RoleRef{Kind: "ClusterRole", Name: "admin"},
+		Subjects: []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "jan"}},
+	}}, plan.Delete)
+
+	// planning must not change anything
+	expectClusterRoleBindings(t, client, expectedCrbs)
+	expectRoleBindings(t, client, []rbacv1.RoleBinding{})
+
+	// the plan-only annotation records the plan in status instead of applying it
+	rbacDef.Annotations = map[string]string{PlanOnlyAnnotationKey: "true"}
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	expectClusterRoleBindings(t, client, expectedCrbs)
+	expectRoleBindings(t, client, []rbacv1.RoleBinding{})
+	assert.Equal(t, plan, rbacDef.Status.Plan)
+	assert.True(t, meta.IsStatusConditionFalse(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady))
+
+	delete(rbacDef.Annotations, PlanOnlyAnnotationKey)
+	err = r.Reconcile(&rbacDef)
+	assert.NoError(t, err)
+	assert.Nil(t, rbacDef.Status.Plan)
+	assert.True(t, meta.IsStatusConditionTrue(rbacDef.Status.Conditions, rbacmanagerv1beta1.ConditionReady))
+}
+
+func TestReconcileNamespaceChangeResetsState(t *testing.T) {
+	client := fake.NewClientset()
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "reset-example"
+	rbacDef.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name:     "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole:       "edit",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "devs"}},
+		}},
+	}}
+
+	// the Reconciler of the namespace controller is shared by every RBAC Definition
+	r := Reconciler{Clientset: client}
+	r.managed.RoleBindings = 3
+	r.failures = map[string]error{"RoleBinding/web/other-devs-edit": errors.New("conflict")}
+	r.pending = []rbacmanagerv1beta1.PlannedChange{{Kind: "ClusterRoleBinding", Name: "other-devs-admin"}}
+	r.adopted = []rbacmanagerv1beta1.PlannedChange{{Kind: "RoleBinding", Name: "other-devs-edit"}}
+
+	err := r.ReconcileNamespaceChange(&rbacDef, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}})
+	assert.NoError(t, err)
+	assert.Empty(t, r.failures)
+	assert.Empty(t, r.pending)
+	assert.Empty(t, r.adopted)
+	assert.Equal(t, 0, r.managed.RoleBindings)
+}
+
 func TestReconcileNamespaceChangesLabels(t *testing.T) {
 	var err error
 
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDefMatchLabels := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDefMatchLabels.Name = "namespace-selector-match-labels"
 
@@ -335,7 +1911,7 @@ func TestReconcileNamespaceChangesLabels(t *testing.T) {
 func TestReconcileNamespaceChangesExpressions(t *testing.T) {
 	var err error
 
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDefMatchExpressions := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDefMatchExpressions.Name = "namespace-selector-match-expressions"
 
@@ -483,7 +2059,7 @@ func TestReconcileNamespaceChangesExpressions(t *testing.T) {
 func TestReconcileNamespaceChangesCRB(t *testing.T) {
 	var err error
 
-	client := fake.NewSimpleClientset()
+	client := fake.NewClientset()
 	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
 	rbacDef.Name = "namespace-selector-empty"
 
@@ -0,0 +1,136 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// Reasons an RBACBinding or one of its entries is outside of its schedule
+const (
+	reasonActive       = "Active"
+	reasonNotYetActive = "NotYetActive"
+	reasonExpired      = "Expired"
+)
+
+// inactiveSchedule describes why something scheduled with notBefore or
+//
+//	expiresAt is not active
+type inactiveSchedule struct {
+	reason  string
+	message string
+}
+
+// checkSchedule returns why something scheduled between notBefore and
+//
+//	expiresAt is inactive, or nil while it is active. The next time this
+//	changes is tracked so the RBAC Definition can be reconciled again then.
+func (p *Parser) checkSchedule(notBefore, expiresAt *metav1.Time) *inactiveSchedule {
+	if notBefore != nil && p.now.Before(notBefore.Time) {
+		p.scheduleChange(notBefore.Time)
+		return &inactiveSchedule{
+			reason:  reasonNotYetActive,
+			message: fmt.Sprintf("not active before %v", notBefore.UTC().Format(time.RFC3339)),
+		}
+	}
+
+	if expiresAt != nil {
+		if !p.now.Before(expiresAt.Time) {
+			return &inactiveSchedule{
+				reason:  reasonExpired,
+				message: fmt.Sprintf("expired at %v", expiresAt.UTC().Format(time.RFC3339)),
+			}
+		}
+		p.scheduleChange(expiresAt.Time)
+	}
+
+	return nil
+}
+
+// scheduleChange keeps track of the earliest time the desired state changes
+func (p *Parser) scheduleChange(t time.Time) {
+	if p.nextChange == nil || t.Before(*p.nextChange) {
+		p.nextChange = &t
+	}
+}
+
+// setInactiveEntry records a Cluster Role Binding or Role Binding entry of an
+//
+//	active RBACBinding that is outside of its schedule
+func (p *Parser) setInactiveEntry(bindingName string, entry string, schedule *inactiveSchedule) {
+	if p.inactiveEntries == nil {
+		p.inactiveEntries = map[string][]inactiveSchedule{}
+	}
+	p.inactiveEntries[bindingName] = append(p.inactiveEntries[bindingName], inactiveSchedule{
+		reason:  schedule.reason,
+		message: fmt.Sprintf("%v %v", entry, schedule.message),
+	})
+}
+
+// setInactiveBinding records an RBACBinding that is outside of its schedule
+func (p *Parser) setInactiveBinding(bindingName string, schedule *inactiveSchedule) {
+	if p.inactiveBindings == nil {
+		p.inactiveBindings = map[string]inactiveSchedule{}
+	}
+	p.inactiveBindings[bindingName] = *schedule
+}
+
+// inactiveCounts returns the number of RBACBindings and entries outside of
+//
+//	their schedule by reason
+func (p *Parser) inactiveCounts() map[string]int {
+	counts := map[string]int{reasonNotYetActive: 0, reasonExpired: 0}
+	for _, schedule := range p.inactiveBindings {
+		counts[schedule.reason]++
+	}
+	for _, schedules := range p.inactiveEntries {
+		for _, schedule := range schedules {
+			counts[schedule.reason]++
+		}
+	}
+	return counts
+}
+
+// isScheduled returns true if an RBACBinding or any of its entries has a schedule
+func isScheduled(rbacBinding *rbacmanagerv1beta1.RBACBinding) bool {
+	if rbacBinding.NotBefore != nil || rbacBinding.ExpiresAt != nil {
+		return true
+	}
+	for _, crb := range rbacBinding.ClusterRoleBindings {
+		if crb.NotBefore != nil || crb.ExpiresAt != nil {
+			return true
+		}
+	}
+	for _, rb := range rbacBinding.RoleBindings {
+		if rb.NotBefore != nil || rb.ExpiresAt != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func validateSchedule(notBefore, expiresAt *metav1.Time, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if notBefore != nil && expiresAt != nil && !expiresAt.After(notBefore.Time) {
+		allErrs = append(allErrs, field.Invalid(path.Child("expiresAt"), expiresAt.UTC().Format(time.RFC3339), "must be after notBefore"))
+	}
+	return allErrs
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/metrics"
+)
+
+// Reasons used for conditions on RBAC Definition status
+const (
+	reasonReconciled    = "Reconciled"
+	reasonValid         = "Valid"
+	reasonParseError    = "ParseError"
+	reasonApplyError    = "ApplyError"
+	reasonInvalidSpec   = "InvalidSpec"
+	reasonDegraded      = "Degraded"
+	reasonNotReconciled = "NotReconciled"
+	reasonPlanOnly      = "PlanOnly"
+	reasonPending       = "PendingApproval"
+)
+
+// recordFailure tracks a resource that could not be created or deleted
+func (r *Reconciler) recordFailure(kind string, objectMeta *metav1.ObjectMeta, err error) {
+	if r.failures == nil {
+		r.failures = map[string]error{}
+	}
+	r.failures[objectKey(kind, objectMeta)] = err
+}
+
+// setStatus records the outcome of a reconciliation on the status of an RBAC Definition
+func (r *Reconciler) setStatus(rbacDef *rbacmanagerv1beta1.RBACDefinition, p *Parser, err error) {
+	now := metav1.Now()
+	status := &rbacDef.Status
+	status.ObservedGeneration = rbacDef.Generation
+	status.LastReconcileTime = &now
+	status.Managed = r.managed
+	status.Plan = r.plan
+	status.PendingApprovals = r.pending
+	status.Adopted = r.adopted
+	status.NextScheduledChange = nil
+	if p.nextChange != nil {
+		nextChange := metav1.NewTime(*p.nextChange)
+		status.NextScheduledChange = &nextChange
+	}
+
+	for reason, count := range p.inactiveCounts() {
+		metrics.InactiveBindingsGauge.WithLabelValues(rbacDef.Namespace, rbacDef.Name, reason).Set(float64(count))
+	}
+
+	// Invalid RBACBindings do not stop the rest of an RBAC Definition from
+	// being reconciled, any other error does
+	invalidSpec := len(p.invalidBindings) > 0 || p.invalidRoles != nil
+	reconcileErr := err
+
+	invalidMessage := ""
+	if invalidSpec {
+		invalidMessage = invalidBindingsMessage(rbacDef, p)
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionInvalidSpec, metav1.ConditionTrue, reasonParseError, invalidMessage)
+	} else {
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionInvalidSpec, metav1.ConditionFalse, reasonValid, "")
+	}
+
+	violationMessage := ""
+	if len(p.violations) > 0 || len(p.roleViolations) > 0 {
+		violationMessage = violationsMessage(rbacDef, p)
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionPolicyViolation, metav1.ConditionTrue, reasonPolicyViolation, violationMessage)
+	} else {
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionPolicyViolation, metav1.ConditionFalse, reasonCompliant, "")
+	}
+
+	switch {
+	case reconcileErr != nil:
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionDegraded, metav1.ConditionTrue, reasonApplyError, reconcileErr.Error())
+	case len(r.failures) > 0:
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionDegraded, metav1.ConditionTrue, reasonApplyError, failureMessage(r.failures))
+	default:
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionDegraded, metav1.ConditionFalse, reasonReconciled, "")
+	}
+
+	switch {
+	case invalidSpec:
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonInvalidSpec, invalidMessage)
+	case reconcileErr != nil || len(r.failures) > 0:
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonDegraded, "Some resources could not be reconciled")
+	case len(p.violations) > 0 || len(p.roleViolations) > 0:
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonPolicyViolation, violationMessage)
+	case len(r.pending) > 0:
+		message := fmt.Sprintf("%d changes require approval by someone other than %q", len(r.pending), rbacDef.Annotations[RequestedByAnnotationKey])
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonPending, message)
+	case !PlanIsEmpty(r.plan):
+		message := fmt.Sprintf("%d changes planned, remove the %v annotation to apply them", len(r.plan.Create)+len(r.plan.Update)+len(r.plan.Delete), PlanOnlyAnnotationKey)
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonPlanOnly, message)
+	default:
+		setCondition(&status.Conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionTrue, reasonReconciled, "")
+	}
+
+	bindingFailures := map[string]map[string]error{}
+	for key, failure := range r.failures {
+		if bindingName, ok := p.bindingNames[key]; ok {
+			if bindingFailures[bindingName] == nil {
+				bindingFailures[bindingName] = map[string]error{}
+			}
+			bindingFailures[bindingName][key] = failure
+		}
+	}
+
+	bindingPending := r.pendingApprovalsByBinding(p)
+
+	bindingStatuses := []rbacmanagerv1beta1.RBACBindingStatus{}
+	for _, rbacBinding := range rbacDef.RBACBindings {
+		bindingStatus := rbacmanagerv1beta1.RBACBindingStatus{
+			Name:              rbacBinding.Name,
+			SkippedNamespaces: p.skippedNamespaces[rdNamePrefix(rbacDef, &rbacBinding)],
+		}
+		for _, previous := range status.RBACBindings {
+			if previous.Name == rbacBinding.Name {
+				bindingStatus.Conditions = previous.Conditions
+				break
+			}
+		}
+
+		conditions := &bindingStatus.Conditions
+		invalid, isInvalid := p.invalidBindings[rbacBinding.Name]
+		switch {
+		case isInvalid:
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionInvalidSpec, metav1.ConditionTrue, reasonParseError, invalid.err.Err.Error())
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonInvalidSpec, invalid.err.Err.Error())
+		case reconcileErr != nil:
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonNotReconciled, reconcileErr.Error())
+		case p.invalidRoles != nil:
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonNotReconciled, "ClusterRoles or Roles are invalid")
+		case len(bindingFailures[rbacBinding.Name]) > 0:
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionInvalidSpec, metav1.ConditionFalse, reasonValid, "")
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionDegraded, metav1.ConditionTrue, reasonApplyError, failureMessage(bindingFailures[rbacBinding.Name]))
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonDegraded, "Some resources could not be reconciled")
+		case len(p.violations[rdNamePrefix(rbacDef, &rbacBinding)]) > 0:
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionInvalidSpec, metav1.ConditionFalse, reasonValid, "")
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonPolicyViolation, "Some resources are forbidden by policy")
+		case bindingPending[rbacBinding.Name] > 0:
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionInvalidSpec, metav1.ConditionFalse, reasonValid, "")
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonPending, "Some bindings require approval")
+		case !PlanIsEmpty(r.plan):
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionInvalidSpec, metav1.ConditionFalse, reasonValid, "")
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionFalse, reasonPlanOnly, "Changes are planned but not applied")
+		default:
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionInvalidSpec, metav1.ConditionFalse, reasonValid, "")
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionDegraded, metav1.ConditionFalse, reasonReconciled, "")
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionReady, metav1.ConditionTrue, reasonReconciled, "")
+		}
+
+		setScheduleCondition(conditions, rbacDef.Generation, &rbacBinding, p, isInvalid)
+
+		if violations := p.violations[rdNamePrefix(rbacDef, &rbacBinding)]; len(violations) > 0 {
+			setCondition(conditions, rbacDef.Generation, rbacmanagerv1beta1.ConditionPolicyViolation, metav1.ConditionTrue, reasonPolicyViolation, strings.Join(violations, "; "))
+		} else {
+			meta.RemoveStatusCondition(conditions, rbacmanagerv1beta1.ConditionPolicyViolation)
+		}
+
+		bindingStatuses = append(bindingStatuses, bindingStatus)
+	}
+	status.RBACBindings = bindingStatuses
+}
+
+// setScheduleCondition reports whether a scheduled RBACBinding is active, along
+//
+//	with any of its entries that are not
+func setScheduleCondition(conditions *[]metav1.Condition, generation int64, rbacBinding *rbacmanagerv1beta1.RBACBinding, p *Parser, invalid bool) {
+	if invalid || !isScheduled(rbacBinding) {
+		meta.RemoveStatusCondition(conditions, rbacmanagerv1beta1.ConditionActive)
+		return
+	}
+
+	if schedule, ok := p.inactiveBindings[rbacBinding.Name]; ok {
+		setCondition(conditions, generation, rbacmanagerv1beta1.ConditionActive, metav1.ConditionFalse, schedule.reason, schedule.message)
+		return
+	}
+
+	messages := []string{}
+	for _, schedule := range p.inactiveEntries[rbacBinding.Name] {
+		messages = append(messages, schedule.message)
+	}
+	setCondition(conditions, generation, rbacmanagerv1beta1.ConditionActive, metav1.ConditionTrue, reasonActive, strings.Join(messages, "; "))
+}
+
+// invalidBindingsMessage describes invalid ClusterRoles and Roles, or every
+//
+//	invalid RBACBinding in the order they are defined
+func invalidBindingsMessage(rbacDef *rbacmanagerv1beta1.RBACDefinition, p *Parser) string {
+	if p.invalidRoles != nil {
+		return p.invalidRoles.Error()
+	}
+	messages := []string{}
+	for _, rbacBinding := range rbacDef.RBACBindings {
+		if invalid, ok := p.invalidBindings[rbacBinding.Name]; ok {
+			messages = append(messages, invalid.err.Error())
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// violationsMessage describes the policy violations of the Roles and
+//
+//	ClusterRoles defined, followed by those of every RBACBinding in the order
+//	they are defined
+func violationsMessage(rbacDef *rbacmanagerv1beta1.RBACDefinition, p *Parser) string {
+	messages := slices.Clone(p.roleViolations)
+	for _, rbacBinding := range rbacDef.RBACBindings {
+		for _, violation := range p.violations[rdNamePrefix(rbacDef, &rbacBinding)] {
+			messages = append(messages, fmt.Sprintf("rbacBinding %q: %v", rbacBinding.Name, violation))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+func setCondition(conditions *[]metav1.Condition, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func failureMessage(failures map[string]error) string {
+	messages := []string{}
+	for key, err := range failures {
+		messages = append(messages, fmt.Sprintf("%v: %v", key, err))
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
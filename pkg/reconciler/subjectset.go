@@ -0,0 +1,107 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+	"log/slog"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+// loadSubjectSets fetches the SubjectSets the first time an RBACBinding refers to one
+func (p *Parser) loadSubjectSets() error {
+	if p.subjectSets != nil || p.LoadSubjectSets == nil {
+		return nil
+	}
+
+	subjectSets, err := p.LoadSubjectSets()
+	if err != nil {
+		slog.Debug("Error listing subject sets", "error", err)
+		return err
+	}
+	p.subjectSets = map[string]rbacmanagerv1beta1.SubjectSet{}
+	for _, subjectSet := range subjectSets.Items {
+		p.subjectSets[subjectSet.Name] = subjectSet
+	}
+	return nil
+}
+
+// expandSubjectSets appends the members of the named SubjectSets and the
+//
+//	SubjectSets they include to members. SubjectSets that include themselves
+//	are invalid.
+func (p *Parser) expandSubjectSets(names []string, expanding, expanded map[string]bool, members *[]rbacv1.Subject) error {
+	for _, name := range names {
+		if expanding[name] {
+			return fmt.Errorf("subject set %q includes itself", name)
+		}
+		if expanded[name] {
+			continue
+		}
+
+		subjectSet, ok := p.subjectSets[name]
+		if !ok {
+			return fmt.Errorf("subject set %q not found", name)
+		}
+
+		expanding[name] = true
+		*members = append(*members, subjectSet.Subjects...)
+		if err := p.expandSubjectSets(subjectSet.SubjectSets, expanding, expanded, members); err != nil {
+			return err
+		}
+		delete(expanding, name)
+		expanded[name] = true
+	}
+	return nil
+}
+
+// ReferencesSubjectSet returns true if an RBAC Definition refers to the named
+//
+//	SubjectSet, either directly or through the other SubjectSets given
+func ReferencesSubjectSet(rbacDef *rbacmanagerv1beta1.RBACDefinition, name string, subjectSets []rbacmanagerv1beta1.SubjectSet) bool {
+	// Every SubjectSet that includes the named one, directly or not
+	including := map[string]bool{name: true}
+	for changed := true; changed; {
+		changed = false
+		for _, subjectSet := range subjectSets {
+			if including[subjectSet.Name] {
+				continue
+			}
+			for _, included := range subjectSet.SubjectSets {
+				if including[included] {
+					including[subjectSet.Name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, rbacBinding := range rbacDef.RBACBindings {
+		for _, subjectSet := range rbacBinding.SubjectSets {
+			if including[subjectSet] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameSubject(a, b rbacv1.Subject) bool {
+	return a.Kind == b.Kind && a.Name == b.Name && a.Namespace == b.Namespace
+}
@@ -0,0 +1,311 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+var rbacBindingsPath = field.NewPath("rbacBindings")
+var clusterRolesPath = field.NewPath("clusterRoles")
+var rolesPath = field.NewPath("roles")
+
+// Validate returns every problem with an RBAC Definition that would prevent
+//
+//	it from being fully reconciled
+func Validate(rbacDef *rbacmanagerv1beta1.RBACDefinition) field.ErrorList {
+	allErrs := field.ErrorList{}
+	bindingNames := map[string]bool{}
+	generatedNames := map[string]bool{}
+
+	allErrs = append(allErrs, validateRoles(rbacDef)...)
+	for _, role := range rbacDef.Roles {
+		generatedNames[fmt.Sprintf("Role/%v/%v", role.Namespace, role.Name)] = true
+	}
+
+	for i, rbacBinding := range rbacDef.RBACBindings {
+		path := rbacBindingsPath.Index(i)
+
+		if bindingNames[rbacBinding.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), rbacBinding.Name))
+		}
+		bindingNames[rbacBinding.Name] = true
+
+		allErrs = append(allErrs, validateRBACBinding(rbacBinding, path)...)
+
+		// Entries that resolve to the same Kubernetes resource would fight over it
+		namePrefix := rdNamePrefix(rbacDef, &rbacBinding)
+		for j, crb := range rbacBinding.ClusterRoleBindings {
+			name := fmt.Sprintf("ClusterRoleBinding/%v-%v", namePrefix, crb.ClusterRole)
+			if generatedNames[name] {
+				allErrs = append(allErrs, field.Duplicate(path.Child("clusterRoleBindings").Index(j), name))
+			}
+			generatedNames[name] = true
+		}
+		for j, rb := range rbacBinding.RoleBindings {
+			if rb.Namespace == "" {
+				continue
+			}
+			roleName := rb.ClusterRole
+			if roleName == "" {
+				roleName = fmt.Sprintf("%v-%v", rb.Role, rb.Namespace)
+			}
+			name := fmt.Sprintf("RoleBinding/%v/%v-%v", rb.Namespace, namePrefix, roleName)
+			if generatedNames[name] {
+				allErrs = append(allErrs, field.Duplicate(path.Child("roleBindings").Index(j), name))
+			}
+			generatedNames[name] = true
+
+			if rb.RoleTemplate != nil {
+				name := fmt.Sprintf("Role/%v/%v", rb.Namespace, rb.Role)
+				if generatedNames[name] {
+					allErrs = append(allErrs, field.Duplicate(path.Child("roleBindings").Index(j).Child("roleTemplate"), name))
+				}
+				generatedNames[name] = true
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateRoles returns the problems with the ClusterRoles and Roles of an
+//
+//	RBAC Definition, these are the same rules the Parser enforces
+func validateRoles(rbacDef *rbacmanagerv1beta1.RBACDefinition) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+
+	for i, cr := range rbacDef.ClusterRoles {
+		path := clusterRolesPath.Index(i)
+		if cr.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+		} else if names["ClusterRole/"+cr.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), cr.Name))
+		}
+		names["ClusterRole/"+cr.Name] = true
+
+		// The controller manager replaces the rules of aggregated ClusterRoles
+		if cr.AggregationRule != nil && len(cr.Rules) > 0 {
+			allErrs = append(allErrs, field.Forbidden(path.Child("rules"), "rules can not be combined with aggregationRule"))
+		}
+		allErrs = append(allErrs, validatePolicyRules(cr.Rules, path.Child("rules"))...)
+	}
+
+	for i, role := range rbacDef.Roles {
+		path := rolesPath.Index(i)
+		if role.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+		}
+		if role.Namespace == "" {
+			allErrs = append(allErrs, field.Required(path.Child("namespace"), ""))
+		}
+		name := fmt.Sprintf("Role/%v/%v", role.Namespace, role.Name)
+		if role.Name != "" && names[name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), role.Name))
+		}
+		names[name] = true
+		allErrs = append(allErrs, validatePolicyRules(role.Rules, path.Child("rules"))...)
+	}
+
+	return allErrs
+}
+
+func validatePolicyRules(rules []rbacv1.PolicyRule, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, rule := range rules {
+		if len(rule.Verbs) == 0 {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("verbs"), ""))
+		}
+	}
+	return allErrs
+}
+
+// validateRBACBinding returns the problems with a single RBACBinding, these
+//
+//	are the same rules the Parser enforces
+func validateRBACBinding(rbacBinding rbacmanagerv1beta1.RBACBinding, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rbacBinding.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+	}
+
+	allErrs = append(allErrs, validateSchedule(rbacBinding.NotBefore, rbacBinding.ExpiresAt, path)...)
+
+	for i, subject := range rbacBinding.Subjects {
+		subjectPath := path.Child("subjects").Index(i)
+		if subject.FromConfigMap != nil {
+			allErrs = append(allErrs, validateConfigMapSubject(subject, subjectPath)...)
+			continue
+		}
+		if subject.Name == "" {
+			allErrs = append(allErrs, field.Required(subjectPath.Child("name"), ""))
+		}
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			// Service Accounts without a namespace are created in the namespace of each Role Binding
+			if subject.Namespace == "" && (len(rbacBinding.RoleBindings) == 0 || len(rbacBinding.ClusterRoleBindings) > 0) {
+				allErrs = append(allErrs, field.Required(subjectPath.Child("namespace"), "namespace is required for ServiceAccount subjects unless they are only bound by Role Bindings"))
+			}
+			for j, tokenSecret := range subject.TokenSecrets {
+				if tokenSecret == "" {
+					allErrs = append(allErrs, field.Required(subjectPath.Child("tokenSecrets").Index(j), ""))
+				}
+			}
+		case rbacv1.UserKind, rbacv1.GroupKind:
+			allErrs = append(allErrs, validateServiceAccountFields(subject, subjectPath)...)
+		default:
+			allErrs = append(allErrs, field.NotSupported(subjectPath.Child("kind"), subject.Kind, []string{rbacv1.GroupKind, rbacv1.ServiceAccountKind, rbacv1.UserKind}))
+		}
+	}
+
+	for i, subjectSet := range rbacBinding.SubjectSets {
+		if subjectSet == "" {
+			allErrs = append(allErrs, field.Required(path.Child("subjectSets").Index(i), ""))
+		}
+	}
+
+	for i, crb := range rbacBinding.ClusterRoleBindings {
+		crbPath := path.Child("clusterRoleBindings").Index(i)
+		if crb.ClusterRole == "" {
+			allErrs = append(allErrs, field.Required(crbPath.Child("clusterRole"), ""))
+		}
+		allErrs = append(allErrs, validateSchedule(crb.NotBefore, crb.ExpiresAt, crbPath)...)
+	}
+
+	for i, rb := range rbacBinding.RoleBindings {
+		allErrs = append(allErrs, validateRoleBinding(rb, path.Child("roleBindings").Index(i))...)
+	}
+
+	return allErrs
+}
+
+// validateConfigMapSubject returns the problems with a subject that lists
+//
+//	its names in a ConfigMap
+func validateConfigMapSubject(subject rbacmanagerv1beta1.Subject, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	refPath := path.Child("fromConfigMap")
+
+	if subject.Name != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("name"), "name can not be combined with fromConfigMap"))
+	}
+	switch subject.Kind {
+	case rbacv1.UserKind, rbacv1.GroupKind:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("kind"), subject.Kind, []string{rbacv1.GroupKind, rbacv1.UserKind}))
+	}
+	allErrs = append(allErrs, validateServiceAccountFields(subject, path)...)
+
+	if subject.FromConfigMap.Namespace == "" {
+		allErrs = append(allErrs, field.Required(refPath.Child("namespace"), ""))
+	}
+	if subject.FromConfigMap.Name == "" {
+		allErrs = append(allErrs, field.Required(refPath.Child("name"), ""))
+	}
+	if subject.FromConfigMap.Key == "" {
+		allErrs = append(allErrs, field.Required(refPath.Child("key"), ""))
+	}
+
+	return allErrs
+}
+
+// validateServiceAccountFields returns the fields set on a User or Group
+//
+//	subject that only apply to the Service Accounts rbac-manager creates
+func validateServiceAccountFields(subject rbacmanagerv1beta1.Subject, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(subject.Labels) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("labels"), "labels are only supported for ServiceAccount subjects"))
+	}
+	if len(subject.Annotations) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("annotations"), "annotations are only supported for ServiceAccount subjects"))
+	}
+	if len(subject.TokenSecrets) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("tokenSecrets"), "tokenSecrets are only supported for ServiceAccount subjects"))
+	}
+
+	return allErrs
+}
+
+func validateRoleBinding(rb rbacmanagerv1beta1.RoleBinding, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rb.ClusterRole == "" && rb.Role == "" {
+		allErrs = append(allErrs, field.Required(path, "role or clusterRole required"))
+	}
+
+	if rb.RoleTemplate != nil {
+		templatePath := path.Child("roleTemplate")
+		if rb.ClusterRole != "" {
+			allErrs = append(allErrs, field.Forbidden(templatePath, "roleTemplate can not be combined with clusterRole"))
+		} else if rb.Role == "" {
+			allErrs = append(allErrs, field.Required(path.Child("role"), "role is required with roleTemplate"))
+		}
+		if len(rb.RoleTemplate.Rules) == 0 {
+			allErrs = append(allErrs, field.Required(templatePath.Child("rules"), ""))
+		}
+		allErrs = append(allErrs, validatePolicyRules(rb.RoleTemplate.Rules, templatePath.Child("rules"))...)
+	}
+
+	if hasNamespaceSelector(&rb) {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(&rb.NamespaceSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("namespaceSelector"))...)
+		if rb.NamespaceNames != nil {
+			allErrs = append(allErrs, validateNamespaceNames(rb.NamespaceNames, path.Child("namespaceNames"))...)
+		}
+	} else if rb.Namespace == "" {
+		allErrs = append(allErrs, field.Required(path, "namespace or namespaceSelector required"))
+	}
+
+	allErrs = append(allErrs, validateSchedule(rb.NotBefore, rb.ExpiresAt, path)...)
+
+	return allErrs
+}
+
+func validateNamespaceNames(names *rbacmanagerv1beta1.NamespaceNameSelector, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch names.Type {
+	case "", rbacmanagerv1beta1.NamespaceNameGlob, rbacmanagerv1beta1.NamespaceNameRegex:
+	default:
+		return append(allErrs, field.NotSupported(path.Child("type"), names.Type, []string{rbacmanagerv1beta1.NamespaceNameGlob, rbacmanagerv1beta1.NamespaceNameRegex}))
+	}
+
+	for i, pattern := range names.Include {
+		if _, err := namePattern(names.Type, pattern); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("include").Index(i), pattern, err.Error()))
+		}
+	}
+	for i, pattern := range names.Exclude {
+		if _, err := namePattern(names.Type, pattern); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("exclude").Index(i), pattern, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// hasNamespaceSelector returns true if a Role Binding selects namespaces by label or by name
+func hasNamespaceSelector(rb *rbacmanagerv1beta1.RoleBinding) bool {
+	return rb.NamespaceSelector.MatchLabels != nil || len(rb.NamespaceSelector.MatchExpressions) > 0 || rb.NamespaceNames != nil
+}
@@ -0,0 +1,381 @@
+// Copyright 2026 FairwindsOps Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+)
+
+var validationTestCases = []struct {
+	name     string
+	given    []rbacmanagerv1beta1.RBACBinding
+	expected []string
+}{
+	{
+		"Valid RBAC Definition",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:     "devs",
+			Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+			ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+				ClusterRole: "view",
+			}},
+			RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+				ClusterRole: "edit",
+				Namespace:   "web",
+			}, {
+				Role:              "custom",
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "devs"}},
+			}},
+		}},
+		[]string{},
+	},
+	{
+		"Role Binding without a role",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:         "devs",
+			Subjects:     []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+			RoleBindings: []rbacmanagerv1beta1.RoleBinding{{Namespace: "web"}},
+		}},
+		[]string{"rbacBindings[0].roleBindings[0]"},
+	},
+	{
+		"Role Binding without a namespace",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:         "devs",
+			Subjects:     []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+			RoleBindings: []rbacmanagerv1beta1.RoleBinding{{ClusterRole: "view"}, {ClusterRole: "edit"}},
+		}},
+		[]string{"rbacBindings[0].roleBindings[0]", "rbacBindings[0].roleBindings[1]"},
+	},
+	{
+		"Malformed namespace selector",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:     "devs",
+			Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+			RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+				ClusterRole: "view",
+				NamespaceSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "team",
+					Operator: metav1.LabelSelectorOpIn,
+				}}},
+			}},
+		}},
+		[]string{"rbacBindings[0].roleBindings[0].namespaceSelector.matchExpressions[0].values"},
+	},
+	{
+		"Malformed namespace name patterns",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:     "devs",
+			Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+			RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+				ClusterRole:    "view",
+				NamespaceNames: &rbacmanagerv1beta1.NamespaceNameSelector{Include: []string{"team-a-*", "team-[b"}},
+			}, {
+				ClusterRole:    "view",
+				NamespaceNames: &rbacmanagerv1beta1.NamespaceNameSelector{Type: rbacmanagerv1beta1.NamespaceNameRegex, Exclude: []string{"(staging"}},
+			}, {
+				ClusterRole:    "view",
+				NamespaceNames: &rbacmanagerv1beta1.NamespaceNameSelector{Type: "Prefix", Include: []string{"team-a"}},
+			}},
+		}},
+		[]string{
+			"rbacBindings[0].roleBindings[0].namespaceNames.include[1]",
+			"rbacBindings[0].roleBindings[1].namespaceNames.exclude[0]",
+			"rbacBindings[0].roleBindings[2].namespaceNames.type",
+		},
+	},
+	{
+		"Expiry before activation",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:      "devs",
+			Subjects:  []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+			NotBefore: &metav1.Time{Time: time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)},
+			ExpiresAt: &metav1.Time{Time: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+			ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{
+				ClusterRole: "view",
+				NotBefore:   &metav1.Time{Time: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+				ExpiresAt:   &metav1.Time{Time: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+			}},
+		}},
+		[]string{"rbacBindings[0].expiresAt", "rbacBindings[0].clusterRoleBindings[0].expiresAt"},
+	},
+	{
+		"Service Account without a namespace",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:     "bots",
+			Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci"}}},
+		}},
+		[]string{"rbacBindings[0].subjects[0].namespace"},
+	},
+	{
+		"Service Account without a namespace bound by Role Bindings",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:     "ci",
+			Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci-runner"}}},
+			RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+				ClusterRole:       "edit",
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "x"}},
+			}},
+		}, {
+			Name:                "ci-cluster",
+			Subjects:            []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci-runner"}}},
+			ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+			RoleBindings:        []rbacmanagerv1beta1.RoleBinding{{ClusterRole: "edit", Namespace: "web"}},
+		}},
+		[]string{"rbacBindings[1].subjects[0].namespace"},
+	},
+	{
+		"Duplicate binding names",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:                "devs",
+			Subjects:            []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+			ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}, {ClusterRole: "view"}},
+		}, {
+			Name:     "devs",
+			Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "sue"}}},
+		}},
+		[]string{"rbacBindings[0].clusterRoleBindings[1]", "rbacBindings[1].name"},
+	},
+	{
+		"Invalid subjects from ConfigMaps",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name: "devs",
+			Subjects: []rbacmanagerv1beta1.Subject{{
+				Subject:       rbacv1.Subject{Kind: rbacv1.UserKind},
+				FromConfigMap: &rbacmanagerv1beta1.ConfigMapKeyReference{Namespace: "identity", Name: "teams", Key: "devs"},
+			}, {
+				Subject:       rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci"},
+				FromConfigMap: &rbacmanagerv1beta1.ConfigMapKeyReference{Name: "teams"},
+			}},
+		}},
+		[]string{
+			"rbacBindings[0].subjects[1].name",
+			"rbacBindings[0].subjects[1].kind",
+			"rbacBindings[0].subjects[1].fromConfigMap.namespace",
+			"rbacBindings[0].subjects[1].fromConfigMap.key",
+		},
+	},
+	{
+		"Service Account fields on other subjects",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name: "devs",
+			Subjects: []rbacmanagerv1beta1.Subject{{
+				Subject:      rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"},
+				Labels:       map[string]string{"team": "dev"},
+				Annotations:  map[string]string{"team": "dev"},
+				TokenSecrets: []string{"joe-token"},
+			}, {
+				Subject:      rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "web"},
+				Labels:       map[string]string{"team": "dev"},
+				TokenSecrets: []string{""},
+			}},
+			ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+		}},
+		[]string{
+			"rbacBindings[0].subjects[0].labels",
+			"rbacBindings[0].subjects[0].annotations",
+			"rbacBindings[0].subjects[0].tokenSecrets",
+			"rbacBindings[0].subjects[1].tokenSecrets[0]",
+		},
+	},
+	{
+		"Invalid role templates",
+		[]rbacmanagerv1beta1.RBACBinding{{
+			Name:     "devs",
+			Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "joe"}}},
+			RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+				ClusterRole:  "edit",
+				Namespace:    "web",
+				RoleTemplate: &rbacmanagerv1beta1.RoleTemplate{Rules: []rbacv1.PolicyRule{{Resources: []string{"pods"}, Verbs: []string{"get"}}}},
+			}, {
+				Role:         "deployer",
+				Namespace:    "api",
+				RoleTemplate: &rbacmanagerv1beta1.RoleTemplate{Rules: []rbacv1.PolicyRule{{Resources: []string{"deployments"}}}},
+			}, {
+				Role:              "deployer",
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "dev"}},
+				RoleTemplate:      &rbacmanagerv1beta1.RoleTemplate{},
+			}},
+		}, {
+			Name:     "bots",
+			Subjects: []rbacmanagerv1beta1.Subject{{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "sue"}}},
+			RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+				Role:         "deployer",
+				Namespace:    "api",
+				RoleTemplate: &rbacmanagerv1beta1.RoleTemplate{Rules: []rbacv1.PolicyRule{{Resources: []string{"deployments"}, Verbs: []string{"get"}}}},
+			}},
+		}},
+		[]string{
+			"rbacBindings[0].roleBindings[0].roleTemplate",
+			"rbacBindings[0].roleBindings[1].roleTemplate.rules[0].verbs",
+			"rbacBindings[0].roleBindings[2].roleTemplate.rules",
+			"rbacBindings[1].roleBindings[0].roleTemplate",
+		},
+	},
+}
+
+func TestValidateRoles(t *testing.T) {
+	rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+	rbacDef.Name = "validation-example"
+	rbacDef.ClusterRoles = []rbacmanagerv1beta1.ClusterRole{{
+		Name:  "secret-reader",
+		Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+	}, {
+		Name:  "secret-reader",
+		Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}}},
+	}, {
+		Name:            "monitoring",
+		AggregationRule: &rbacv1.AggregationRule{},
+		Rules:           []rbacv1.PolicyRule{{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}}},
+	}}
+	rbacDef.Roles = []rbacmanagerv1beta1.Role{{
+		Name:      "secret-reader",
+		Namespace: "web",
+	}, {
+		Name: "deployer",
+	}}
+
+	fields := []string{}
+	for _, err := range Validate(&rbacDef) {
+		fields = append(fields, err.Field)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"clusterRoles[1].name",
+		"clusterRoles[1].rules[0].verbs",
+		"clusterRoles[2].rules",
+		"roles[1].namespace",
+	}, fields)
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range validationTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rbacDef := rbacmanagerv1beta1.RBACDefinition{}
+			rbacDef.Name = "validation-example"
+			rbacDef.RBACBindings = tc.given
+
+			fields := []string{}
+			for _, err := range Validate(&rbacDef) {
+				fields = append(fields, err.Field)
+			}
+
+			assert.ElementsMatch(t, tc.expected, fields)
+		})
+	}
+}
+
+func TestValidateNamespaced(t *testing.T) {
+	nrd := rbacmanagerv1beta1.NamespacedRBACDefinition{}
+	nrd.Name = "validation-example"
+	nrd.Namespace = "team-a"
+	nrd.RBACBindings = []rbacmanagerv1beta1.RBACBinding{{
+		Name: "devs",
+		Subjects: []rbacmanagerv1beta1.Subject{
+			{Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci"}},
+			{Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "team-a"}},
+			{Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "team-b"}},
+			{Subject: rbacv1.Subject{Kind: rbacv1.UserKind}, FromConfigMap: &rbacmanagerv1beta1.ConfigMapKeyReference{Name: "teams", Key: "a"}},
+			{Subject: rbacv1.Subject{Kind: rbacv1.UserKind}, FromConfigMap: &rbacmanagerv1beta1.ConfigMapKeyReference{Namespace: "identity", Name: "teams", Key: "a"}},
+		},
+		SubjectSets:         []string{"sre"},
+		ClusterRoleBindings: []rbacmanagerv1beta1.ClusterRoleBinding{{ClusterRole: "view"}},
+		RoleBindings: []rbacmanagerv1beta1.RoleBinding{{
+			ClusterRole: "edit",
+		}, {
+			Role:      "deployer",
+			Namespace: "team-a",
+		}, {
+			ClusterRole: "edit",
+			Namespace:   "team-b",
+		}, {
+			ClusterRole:       "view",
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+		}, {
+			ClusterRole: "cluster-admin",
+		}, {
+			Role:         "secret-reader",
+			RoleTemplate: &rbacmanagerv1beta1.RoleTemplate{Rules: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}}},
+		}},
+	}}
+
+	fields := []string{}
+	for _, err := range ValidateNamespaced(&nrd, sets.New("edit", "view")) {
+		fields = append(fields, err.Field)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"rbacBindings[0].subjects[2].namespace",
+		"rbacBindings[0].subjects[4].fromConfigMap.namespace",
+		"rbacBindings[0].subjectSets",
+		"rbacBindings[0].clusterRoleBindings",
+		"rbacBindings[0].roleBindings[2].namespace",
+		"rbacBindings[0].roleBindings[3]",
+		"rbacBindings[0].roleBindings[4].clusterRole",
+		"rbacBindings[0].roleBindings[5].roleTemplate",
+	}, fields)
+}
+
+// The API server prunes fields missing from the schema before the webhook sees
+// them, so every field ValidateNamespaced rejects has to be part of the schema
+func TestNamespacedRBACDefinitionSchema(t *testing.T) {
+	content, err := os.ReadFile("../../deploy/2_crd.yaml")
+	assert.NoError(t, err)
+
+	type schema struct {
+		Properties map[string]schema `json:"properties"`
+		Items      *schema           `json:"items"`
+	}
+	type crd struct {
+		Spec struct {
+			Names    struct{ Kind string } `json:"names"`
+			Versions []struct {
+				Schema struct {
+					OpenAPIV3Schema schema `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+
+	for _, document := range strings.Split(string(content), "\n---\n") {
+		definition := crd{}
+		assert.NoError(t, yaml.Unmarshal([]byte(document), &definition))
+		if definition.Spec.Names.Kind != "NamespacedRBACDefinition" {
+			continue
+		}
+
+		rbacBinding := definition.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["rbacBindings"].Items
+		assert.Contains(t, rbacBinding.Properties, "clusterRoleBindings")
+		assert.Contains(t, rbacBinding.Properties, "subjectSets")
+
+		roleBinding := rbacBinding.Properties["roleBindings"].Items
+		assert.Contains(t, roleBinding.Properties, "namespaceSelector")
+		assert.Contains(t, roleBinding.Properties, "namespaceNames")
+		assert.Contains(t, roleBinding.Properties, "roleTemplate")
+		return
+	}
+	t.Fatal("NamespacedRBACDefinition CRD not found")
+}
@@ -1,29 +0,0 @@
-/*
-Copyright 2019 FairwindsOps Inc.
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-    http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/
-
-package watcher
-
-import (
-	"github.com/fairwindsops/rbac-manager/pkg/kube"
-)
-
-// WatchRelatedResources watches all resources owned by RBAC Definitions
-func WatchRelatedResources() {
-	clientset := kube.GetClientsetOrDie()
-	go watchClusterRoleBindings(clientset)
-	go watchRoleBindings(clientset)
-	go watchServiceAccounts(clientset)
-}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+)
+
+// RecordApprovalPath is the path the mutating webhook that records who
+// requested and approved RBAC Definitions is served on
+const RecordApprovalPath = "/mutate-approval"
+
+// approvalRecorder sets the approval annotations of RBAC Definitions and
+// Namespaced RBAC Definitions from the user making each request
+type approvalRecorder struct{}
+
+// Handle records the requesting user in the approval annotations
+func (h *approvalRecorder) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var oldAnnotations map[string]string
+	specChanged := true
+	if req.Operation == admissionv1.Update {
+		oldObj := &unstructured.Unstructured{}
+		if err := oldObj.UnmarshalJSON(req.OldObject.Raw); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		oldAnnotations = oldObj.GetAnnotations()
+		specChanged = !equality.Semantic.DeepEqual(spec(oldObj), spec(obj))
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	reconciler.RecordApproval(annotations, oldAnnotations, specChanged, req.UserInfo.Username)
+	obj.SetAnnotations(annotations)
+
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// spec returns the fields of an RBAC Definition that describe what it grants,
+// which are at the top level of the object along with its metadata
+func spec(obj *unstructured.Unstructured) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for key, value := range obj.Object {
+		switch key {
+		case "apiVersion", "kind", "metadata", "status":
+		default:
+			fields[key] = value
+		}
+	}
+	return fields
+}
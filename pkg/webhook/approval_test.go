@@ -0,0 +1,84 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+)
+
+func TestRecordApproval(t *testing.T) {
+	approved := rbacmanagerv1beta1.RBACDefinition{}
+	approved.APIVersion = rbacmanagerv1beta1.SchemeGroupVersion.String()
+	approved.Kind = "RBACDefinition"
+	approved.Name = "approval-example"
+	approved.Annotations = map[string]string{
+		reconciler.RequestedByAnnotationKey: "joe",
+		reconciler.ApprovedByAnnotationKey:  "jane",
+	}
+	approved.ClusterRoles = []rbacmanagerv1beta1.ClusterRole{{
+		Name:  "deployer",
+		Rules: []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}}},
+	}}
+
+	// changing a ClusterRole the bindings refer to needs a new approval
+	changed := approved.DeepCopy()
+	changed.ClusterRoles[0].Rules[0].Verbs = []string{"*"}
+	patches := handleUpdate(t, &approved, changed, "joe")
+	assert.Equal(t, []jsonpatch.JsonPatchOperation{
+		jsonpatch.NewOperation("remove", "/metadata/annotations/rbacmanager.reactiveops.io~1approved-by", nil),
+	}, patches)
+
+	// other changes keep the approval
+	labeled := approved.DeepCopy()
+	labeled.Labels = map[string]string{"team": "platform"}
+	patches = handleUpdate(t, &approved, labeled, "sue")
+	assert.Empty(t, patches)
+}
+
+// handleUpdate returns the patches the approval webhook makes to an update
+//
+//	of oldObj to newObj by username
+func handleUpdate(t *testing.T, oldObj, newObj *rbacmanagerv1beta1.RBACDefinition, username string) []jsonpatch.JsonPatchOperation {
+	h := &approvalRecorder{}
+	resp := h.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		UserInfo:  authenticationv1.UserInfo{Username: username},
+		Object:    runtime.RawExtension{Raw: marshal(t, newObj)},
+		OldObject: runtime.RawExtension{Raw: marshal(t, oldObj)},
+	}})
+	assert.True(t, resp.Allowed)
+	return resp.Patches
+}
+
+func marshal(t *testing.T, obj runtime.Object) []byte {
+	raw, err := json.Marshal(obj)
+	assert.NoError(t, err)
+	return raw
+}
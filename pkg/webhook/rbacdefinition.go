@@ -0,0 +1,115 @@
+/*
+Copyright 2026 FairwindsOps Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	rbacmanagerv1beta1 "github.com/fairwindsops/rbac-manager/pkg/apis/rbacmanager/v1beta1"
+	"github.com/fairwindsops/rbac-manager/pkg/reconciler"
+)
+
+// ValidateRBACDefinitionPath is the path the RBAC Definition validating webhook is served on
+const ValidateRBACDefinitionPath = "/validate-rbacdefinition"
+
+// ValidateNamespacedRBACDefinitionPath is the path the Namespaced RBAC Definition validating webhook is served on
+const ValidateNamespacedRBACDefinitionPath = "/validate-namespacedrbacdefinition"
+
+// Add registers the RBAC Manager admission webhooks with the Manager's webhook server
+func Add(mgr manager.Manager, options reconciler.Options) error {
+	mgr.GetWebhookServer().Register(ValidateRBACDefinitionPath,
+		admission.WithCustomValidator(mgr.GetScheme(), &rbacmanagerv1beta1.RBACDefinition{}, &rbacDefValidator{}))
+	mgr.GetWebhookServer().Register(ValidateNamespacedRBACDefinitionPath,
+		admission.WithCustomValidator(mgr.GetScheme(), &rbacmanagerv1beta1.NamespacedRBACDefinition{}, &namespacedRbacDefValidator{allowedClusterRoles: options.NamespacedClusterRoles}))
+	mgr.GetWebhookServer().Register(RecordApprovalPath, &admission.Webhook{Handler: &approvalRecorder{}})
+	return nil
+}
+
+// rbacDefValidator rejects RBAC Definitions that can't be fully reconciled
+type rbacDefValidator struct{}
+
+// ValidateCreate validates a new RBAC Definition
+func (v *rbacDefValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateRbacDefinition(obj)
+}
+
+// ValidateUpdate validates changes to an existing RBAC Definition
+func (v *rbacDefValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, validateRbacDefinition(newObj)
+}
+
+// ValidateDelete allows every RBAC Definition to be deleted
+func (v *rbacDefValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateRbacDefinition(obj runtime.Object) error {
+	rbacDef, ok := obj.(*rbacmanagerv1beta1.RBACDefinition)
+	if !ok {
+		return fmt.Errorf("expected an RBACDefinition but got %T", obj)
+	}
+
+	errs := reconciler.Validate(rbacDef)
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(rbacmanagerv1beta1.SchemeGroupVersion.WithKind("RBACDefinition").GroupKind(), rbacDef.Name, errs)
+	}
+
+	return nil
+}
+
+// namespacedRbacDefValidator rejects Namespaced RBAC Definitions that can't be
+// fully reconciled, that request resources outside of their namespace, or that
+// bind ClusterRoles that are not allowed
+type namespacedRbacDefValidator struct {
+	allowedClusterRoles sets.Set[string]
+}
+
+// ValidateCreate validates a new Namespaced RBAC Definition
+func (v *namespacedRbacDefValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateNamespacedRbacDefinition(obj, v.allowedClusterRoles)
+}
+
+// ValidateUpdate validates changes to an existing Namespaced RBAC Definition
+func (v *namespacedRbacDefValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, validateNamespacedRbacDefinition(newObj, v.allowedClusterRoles)
+}
+
+// ValidateDelete allows every Namespaced RBAC Definition to be deleted
+func (v *namespacedRbacDefValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateNamespacedRbacDefinition(obj runtime.Object, allowedClusterRoles sets.Set[string]) error {
+	nrd, ok := obj.(*rbacmanagerv1beta1.NamespacedRBACDefinition)
+	if !ok {
+		return fmt.Errorf("expected a NamespacedRBACDefinition but got %T", obj)
+	}
+
+	errs := reconciler.ValidateNamespaced(nrd, allowedClusterRoles)
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(rbacmanagerv1beta1.SchemeGroupVersion.WithKind("NamespacedRBACDefinition").GroupKind(), nrd.Name, errs)
+	}
+
+	return nil
+}